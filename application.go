@@ -2,6 +2,7 @@ package application
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	fiberv2 "github.com/gofiber/fiber/v2"
 	"github.com/jamillosantos/config"
@@ -17,9 +19,7 @@ import (
 	"github.com/jamillosantos/logctx"
 	srvfiber "github.com/jamillosantos/server-fiber"
 	svchealthcheck "github.com/jamillosantos/services-healthcheck"
-	"github.com/jamillosantos/services-healthcheck/hcfiber"
 	"go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
 
 	"github.com/jamillosantos/application/zapreporter"
 )
@@ -54,14 +54,43 @@ type Application struct {
 	buildDate string
 	goVersion string
 
+	withVersionUsed bool
+
 	loggerZapOptions    []zap.Option
+	loggerLevel         zap.AtomicLevel
+	loggerSampler       *logSampler
+	droppedLogFields    map[string][]string
 	disableSystemServer bool
 
 	environment string
 
-	skipConfig    bool
-	ConfigManager *config.Manager
-	Runner        *goservices.Runner
+	skipConfig      bool
+	configFlagSet   *flag.FlagSet
+	configEnv       bool
+	configEnvPrefix string
+	configEngines   []config.Engine
+	ConfigManager   *config.Manager
+	Runner          *goservices.Runner
+
+	runner           *goservices.Runner
+	runnerObserver   *runnerObserver
+	runnerObserverOf *goservices.Runner
+	skipReporter     bool
+
+	readinessGroups         []readinessGroup
+	readinessHistorySize    int
+	readinessErrorRedactor  func(message string) string
+	readinessWarmup         bool
+	readinessWarmupBlocking bool
+
+	backgroundCheckInterval time.Duration
+
+	startupReportHandler func(StartupReport)
+
+	metricsNamespace string
+
+	exitOnUnhealthyAfter  time.Duration
+	exitOnUnhealthyChecks []string
 
 	shutdownHandlerMutex sync.Mutex
 	shutdownHandler      []func()
@@ -100,6 +129,7 @@ func (app *Application) WithName(value string) *Application {
 // Deprecated: Now the versions are extracted automatically from the go1.18 buildinfo.
 func (app *Application) WithVersion(version, build, buildDate string) *Application {
 	app.version, app.build, app.buildDate = version, build, buildDate
+	app.withVersionUsed = true
 	return app
 }
 
@@ -113,6 +143,17 @@ func (app *Application) WithZapConfigModifier(f func(*zap.Config)) *Application
 	return app
 }
 
+// WithDroppedLogFields removes the fields with the given keys from all log entries when the application runs in the
+// given environment. Useful to drop noisy or high-cardinality fields (like full request bodies) in production while
+// keeping them in development.
+func (app *Application) WithDroppedLogFields(environment string, keys ...string) *Application {
+	if app.droppedLogFields == nil {
+		app.droppedLogFields = make(map[string][]string)
+	}
+	app.droppedLogFields[environment] = append(app.droppedLogFields[environment], keys...)
+	return app
+}
+
 func (app *Application) WithEnvironment(environment string) *Application {
 	app.environment = environment
 	return app
@@ -129,6 +170,134 @@ func (app *Application) WithSkipConfig(skip bool) *Application {
 	return app
 }
 
+// WithReadinessGroup declares a group of services whose readiness is aggregated using the given mode. With AnyReady,
+// the application is ready when any of the services is ready, which models active/standby and failover topologies.
+// Services that belong to a group are evaluated only through their group. When the group is not ready, the readyz
+// payload reports it under the "group:<name>" entry.
+//
+// It panics if mode is neither AllReady nor AnyReady, or if no service is given.
+func (app *Application) WithReadinessGroup(name string, mode ReadinessMode, services ...string) *Application {
+	if !mode.isValid() {
+		panic(fmt.Errorf("%w: %q", ErrInvalidReadinessMode, mode))
+	}
+	if len(services) == 0 {
+		panic(fmt.Errorf("%w: %q", ErrEmptyReadinessGroup, name))
+	}
+	app.readinessGroups = append(app.readinessGroups, readinessGroup{
+		name:     name,
+		mode:     mode,
+		services: services,
+	})
+	return app
+}
+
+// WithFlagConfig adds the given flag.FlagSet as a configuration source with the highest priority. The precedence order
+// is: flags > env (see WithEnvConfig) > secret/plain files. Only flags explicitly set in the command line override the
+// other sources, and the flag name maps to the configuration key replacing "-" with "." (`--db-host` overrides
+// `db.host`).
+//
+// The flagSet must be parsed before the application runs.
+func (app *Application) WithFlagConfig(flagSet *flag.FlagSet) *Application {
+	app.configFlagSet = flagSet
+	return app
+}
+
+// WithEnvConfig adds the environment variables as a configuration source, overriding the secret/plain files and
+// overridden by the flags (see WithFlagConfig). The configuration key maps to the variable name replacing "." and "-"
+// with "_", upper casing it and adding the prefix (with the "MYAPP" prefix, `MYAPP_DB_HOST` overrides `db.host`).
+// Slices are given as comma separated values.
+//
+// It panics if the prefix is empty, since common variables (eg: PORT, HOME, PATH) would silently override the
+// configuration.
+func (app *Application) WithEnvConfig(prefix string) *Application {
+	if prefix == "" {
+		panic(ErrEmptyEnvConfigPrefix)
+	}
+	app.configEnv = true
+	app.configEnvPrefix = prefix
+	return app
+}
+
+// WithReadinessFailurePayload keeps the last size errors of each ready check, with their timestamps, and includes them
+// in the readyz payload under each check. It helps telling transient from persistent failures. A size of 0 (default)
+// disables the history.
+func (app *Application) WithReadinessFailurePayload(size int) *Application {
+	app.readinessHistorySize = size
+	return app
+}
+
+// WithReadinessErrorRedactor sets a function to redact the error messages exposed by the readyz payload, including the
+// recent errors history.
+func (app *Application) WithReadinessErrorRedactor(redact func(message string) string) *Application {
+	app.readinessErrorRedactor = redact
+	return app
+}
+
+// WithReadinessWarmup evaluates all ready checks once, after the services start and before the application is flagged
+// as ready, so the first external probe does not pay for cold check paths or lazy connections. Warmup failures are
+// logged.
+//
+// When blockOnFailure is true and any check fails, the warmup is retried every second and the application is not
+// flagged as ready until all checks pass. If the application shuts down before that, the startup is reported as
+// StartupFailed. Otherwise, the failures do not block the readiness.
+func (app *Application) WithReadinessWarmup(warmup, blockOnFailure bool) *Application {
+	app.readinessWarmup = warmup
+	app.readinessWarmupBlocking = blockOnFailure
+	return app
+}
+
+// WithBackgroundCheckInterval runs all registered checks periodically, regardless of external probes. The health and
+// ready endpoints are served from the results of the last run instead of running the checks on each request. A zero
+// interval (default) disables the background checks.
+func (app *Application) WithBackgroundCheckInterval(interval time.Duration) *Application {
+	app.backgroundCheckInterval = interval
+	return app
+}
+
+// WithStartupReport sets a callback that receives the StartupReport once the startup finishes, either successfully
+// or not. Useful for integration tests and tooling.
+func (app *Application) WithStartupReport(handler func(StartupReport)) *Application {
+	app.startupReportHandler = handler
+	return app
+}
+
+// WithMetricsNamespace sets the namespace applied to every metric registered for the application. It defaults to the
+// sanitized application name. Check MetricsNamespace.
+func (app *Application) WithMetricsNamespace(namespace string) *Application {
+	app.metricsNamespace = sanitizeMetricsNamespace(namespace)
+	return app
+}
+
+// WithExitOnUnhealthy initiates the graceful shutdown of the application when any of the given health or ready checks
+// stays failing longer than after. Then, the application exits with an error, so it can be rescheduled. Useful when a
+// critical dependency is permanently down.
+//
+// The checks are evaluated by the background checks (see WithBackgroundCheckInterval). If the background checks are
+// not enabled, only the given checks are evaluated every 5s on their own, without caching the results of the health
+// and ready endpoints nor recording their recent errors.
+func (app *Application) WithExitOnUnhealthy(after time.Duration, checkNames ...string) *Application {
+	app.exitOnUnhealthyAfter = after
+	app.exitOnUnhealthyChecks = checkNames
+	return app
+}
+
+// WithRunner sets a pre-configured goservices.Runner to be used instead of the one created by the application. The
+// application still attaches its reporter and the observer that registers the health and ready checks. The observers
+// are attached only once, even when the application runs multiple times with the same runner.
+//
+// If the runner already has a reporter, every service event is logged twice. Use WithSkipReporter to prevent that.
+func (app *Application) WithRunner(runner *goservices.Runner) *Application {
+	app.runner = runner
+	return app
+}
+
+// WithSkipReporter skips attaching the zapreporter to the runner. Useful when the runner given to WithRunner already
+// has a reporter.
+func (app *Application) WithSkipReporter(skip bool) *Application {
+	app.skipReporter = skip
+	return app
+}
+
 func (app *Application) Shutdown(handler func()) *Application {
 	app.shutdownHandlerMutex.Lock()
 	app.shutdownHandler = append(app.shutdownHandler, handler)
@@ -143,25 +312,21 @@ func (app *Application) Run(setup ServiceSetup) {
 	}
 }
 
-func (app *Application) run(setup ServiceSetup) error {
-	var (
-		logger *zap.Logger
-		err    error
-	)
+// RunE runs the application like Run, but returns the error instead of exiting the process.
+func (app *Application) RunE(setup ServiceSetup) error {
+	return app.run(setup)
+}
 
-	var zapcfg zap.Config
-	switch app.environment {
-	case "dev":
-		zapcfg = zap.NewDevelopmentConfig()
-	default:
-		zapcfg = zap.NewProductionConfig()
-	}
-	if app.zapConfigModifier != nil {
-		app.zapConfigModifier(&zapcfg)
-	}
-	zapcfg.DisableStacktrace = true
-	zapcfg.EncoderConfig.EncodeTime = zapcore.RFC3339NanoTimeEncoder
-	logger, err = zapcfg.Build(app.loggerZapOptions...)
+func (app *Application) run(setup ServiceSetup) (err error) {
+	var logger *zap.Logger
+
+	startup := newStartupReporter(app.startupReportHandler)
+	defer func() {
+		// If the startup did not finish, it failed.
+		startup.finish(StartupFailed, err)
+	}()
+
+	logger, err = app.buildLogger()
 	if err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, "failed initialising logger:", err.Error())
 		return err
@@ -179,26 +344,64 @@ func (app *Application) run(setup ServiceSetup) error {
 		zap.String("go_version", app.goVersion),
 	)
 
+	app.warnDeprecations(logger)
+
 	ctx, cancelFunc := signal.NotifyContext(app.context, os.Interrupt, syscall.SIGTERM)
 	defer cancelFunc()
 
+	ctx, shutdown := context.WithCancel(ctx)
+	defer shutdown()
+
 	ctx = logctx.WithLogger(ctx, logger)
 
+	// Registered before the runner is finished, so SIGPIPE is still handled while the shutdown logs are written.
+	stopSIGPIPE := handleSIGPIPE(logger)
+	defer stopSIGPIPE()
+
 	// Initializes the default logger instance
 	err = logctx.Initialize(logctx.WithDefaultLogger(logger))
 	if err != nil {
 		return err
 	}
 
-	hc := svchealthcheck.NewHealthcheck(
-		svchealthcheck.WithReadyCheck("app", &appChecker{app}),
-	)
-	hcObserver := newHealthchekcObserver(hc)
+	hc := svchealthcheck.NewHealthcheck()
+	hcObserver := newHealthchekcObserver(hc, app.readinessHistorySize, app.readinessErrorRedactor)
+	// The app check is not recorded in the recent errors history, since it is expected to fail until the application
+	// is running.
+	hc.AddReadyCheck(appCheckName, &appChecker{app})
+	readiness := newReadinessAggregator(hc, hcObserver, app.readinessGroups)
+
+	checkInterval := app.backgroundCheckInterval
+	if len(app.exitOnUnhealthyChecks) > 0 {
+		readiness.monitor = newUnhealthyMonitor(app.exitOnUnhealthyAfter, app.exitOnUnhealthyChecks, func(reason string) {
+			logger.Error("initiating graceful shutdown", zap.String("reason", reason))
+			shutdown()
+		})
+	}
 
-	app.Runner = goservices.NewRunner(
-		goservices.WithReporter(zapreporter.New(logger)),
-		goservices.WithObserver(hcObserver),
-	)
+	observers := make([]goservices.Observer, 0, 3)
+	if !app.skipReporter {
+		observers = append(observers, zapreporter.New(logger))
+	}
+	observers = append(observers, hcObserver, startup)
+
+	if app.runner != nil {
+		// The observers are attached to the given runner only once, through a runnerObserver, so running the
+		// application again does not duplicate them.
+		if app.runnerObserverOf != app.runner {
+			app.runnerObserver = &runnerObserver{}
+			goservices.WithObserver(app.runnerObserver)(app.runner)
+			app.runnerObserverOf = app.runner
+		}
+		app.runnerObserver.set(observers...)
+		app.Runner = app.runner
+	} else {
+		runnerOpts := make([]goservices.StarterOption, 0, len(observers))
+		for _, observer := range observers {
+			runnerOpts = append(runnerOpts, goservices.WithObserver(observer))
+		}
+		app.Runner = goservices.NewRunner(runnerOpts...)
+	}
 	defer func() {
 		r := recover()
 		if r != nil {
@@ -213,37 +416,37 @@ func (app *Application) run(setup ServiceSetup) error {
 		_ = logger.Sync()
 	}()
 
-	if err := app.runSystemServer(ctx, hc); err != nil {
+	if err := app.runSystemServer(ctx, readiness); err != nil {
 		logger.Error("failed to start system server", zap.Error(err))
 		return err
 	}
 
+	if checkInterval > 0 {
+		if err := app.Runner.Run(ctx, newBackgroundChecker("background checks", checkInterval, readiness.refresh)); err != nil {
+			logger.Error("failed to start the background checks", zap.Error(err))
+			return err
+		}
+	} else if readiness.monitor != nil {
+		// Without the background checks, the critical checks are evaluated on their own, not caching the endpoints.
+		unhealthyChecker := newBackgroundChecker("unhealthy checks", exitOnUnhealthyCheckInterval, readiness.observeUnhealthy)
+		if err := app.Runner.Run(ctx, unhealthyChecker); err != nil {
+			logger.Error("failed to start the unhealthy checks", zap.Error(err))
+			return err
+		}
+	}
+
 	if !app.skipConfig {
-		// Initializes and load the plain configuration
-		plainConfigLoader := config.NewFileLoader(goenv.GetStringDefault("CONFIG", ".config.yaml"))
-		plainEngine := config.NewYAMLEngine(plainConfigLoader)
-		err = plainEngine.Load()
+		err = app.loadConfig()
 		if err != nil {
-			logger.Error("could not initialize the plain engine", zap.Error(err))
+			logger.Error("could not load the configuration", zap.Error(err))
 			return err
 		}
 
-		// Initializes tand load the secret configuration
-		secretConfigLoader := config.NewFileLoader(goenv.GetStringDefault("SECRETS", ".secrets.yaml"))
-		secretEngine := config.NewYAMLEngine(secretConfigLoader)
-		err = secretEngine.Load()
+		err = app.applyLogConfig(ctx)
 		if err != nil {
-			logger.Error("could not initialize the secret engine", zap.Error(err))
+			logger.Error("could not apply the log configuration", zap.Error(err))
 			return err
 		}
-
-		configManager := config.NewManager()
-		configManager.AddPlainEngine(plainEngine)
-		configManager.AddSecretEngine(secretEngine)
-
-		// Publish the config manager to be used into the setup callback
-		app.ConfigManager = configManager
-
 	}
 
 	svcs, err := setup(ctx, app)
@@ -254,6 +457,7 @@ func (app *Application) run(setup ServiceSetup) error {
 
 	// No need to run the services if there is no service to run.
 	if len(svcs) == 0 {
+		startup.finish(StartupNoServices, nil)
 		return nil
 	}
 
@@ -263,13 +467,33 @@ func (app *Application) run(setup ServiceSetup) error {
 		return err
 	}
 
+	if app.readinessWarmup {
+		if app.readinessWarmupBlocking {
+			if err := readiness.warmupUntilReady(ctx, readinessWarmupRetryInterval); err != nil {
+				// The application is shutting down before passing the warmup, so it is never flagged as ready.
+				logger.Warn("readiness warmup interrupted", zap.Error(err))
+				startup.finish(StartupFailed, err)
+				return readiness.unhealthyError()
+			}
+		} else {
+			readiness.warmup(ctx)
+		}
+	}
+
 	app.stateM.Lock()
 	app.state = stateRunning
 	app.stateM.Unlock()
 
+	if checkInterval > 0 {
+		// Refreshes the cached results so the readiness is reported without waiting for the next interval.
+		readiness.refresh(ctx)
+	}
+
+	startup.finish(StartupRunning, nil)
+
 	<-ctx.Done()
 
-	return nil
+	return readiness.unhealthyError()
 }
 
 // extractServiceName extracts the service name from the repository path.
@@ -309,19 +533,28 @@ func findSettingsIfEmpty(bi *debug.BuildInfo, key, value, value2, defaultValue s
 }
 
 // buildSystemServer initializes the server for metrics.
-func (app *Application) buildSystemServer(hc *svchealthcheck.Healthcheck) *srvfiber.FiberServer {
+func (app *Application) buildSystemServer(readiness *readinessAggregator) *srvfiber.FiberServer {
 	return srvfiber.NewFiberServer(func(app *fiberv2.App) error {
-		hcfiber.FiberInitialize(hc, app)
+		// The checks get the user context instead of the fasthttp request context, which is recycled after the handler
+		// returns while a timed out check may still be using it.
+		app.Get(svchealthcheck.HealthPath, func(ctx *fiberv2.Ctx) error {
+			r := readiness.Health(ctx.UserContext())
+			return ctx.Status(r.StatusCode).JSON(r)
+		})
+		app.Get(svchealthcheck.ReadyPath, func(ctx *fiberv2.Ctx) error {
+			r := readiness.Ready(ctx.UserContext())
+			return ctx.Status(r.StatusCode).JSON(r)
+		})
 		return nil
 	}, srvfiber.WithName("metrics/health/live"), srvfiber.WithBindAddress(":8082"))
 }
 
 // runSystemServer starts the server for metrics, health and ready checks. If the disableSystemServer flag is set,
 // this function does nothing returning no error.
-func (app *Application) runSystemServer(ctx context.Context, hc *svchealthcheck.Healthcheck) error {
+func (app *Application) runSystemServer(ctx context.Context, readiness *readinessAggregator) error {
 	if app.disableSystemServer {
 		return nil
 	}
-	systemServer := app.buildSystemServer(hc)
+	systemServer := app.buildSystemServer(readiness)
 	return app.Runner.Run(ctx, systemServer)
 }
@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path"
 	"runtime/debug"
+	"sync"
 	"testing"
 	"time"
 
@@ -18,6 +20,8 @@ import (
 	svchealthcheck "github.com/jamillosantos/services-healthcheck"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestApplication_WithContext(t *testing.T) {
@@ -32,6 +36,28 @@ func TestApplication_WithVersion(t *testing.T) {
 	assert.Equal(t, wantVersion, app.version)
 	assert.Equal(t, wantBuild, app.build)
 	assert.Equal(t, wantBuildDate, app.buildDate)
+	assert.True(t, app.withVersionUsed)
+}
+
+func TestApplication_warnDeprecations(t *testing.T) {
+	// The deprecation is logged once per process, so it is reset to not depend on other tests.
+	resetOnce := func() { withVersionDeprecationOnce = sync.Once{} }
+	resetOnce()
+	t.Cleanup(resetOnce)
+
+	core, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(core)
+
+	New().warnDeprecations(logger)
+	assert.Equal(t, 0, logs.Len(), "should not warn when WithVersion is not used")
+
+	app := New().WithVersion("version", "build", "build_date")
+	app.warnDeprecations(logger)
+	app.warnDeprecations(logger)
+	New().WithVersion("version", "build", "build_date").warnDeprecations(logger)
+
+	require.Equal(t, 1, logs.Len(), "should warn only once per process")
+	assert.Equal(t, "Application.WithVersion", logs.All()[0].ContextMap()["deprecated"])
 }
 
 func TestApplication_WithEnvironment(t *testing.T) {
@@ -46,6 +72,49 @@ func TestApplication_Shutdown(t *testing.T) {
 	require.Len(t, app.shutdownHandler, 1)
 }
 
+func TestApplication_WithRunner(t *testing.T) {
+	t.Run("should use the given runner", func(t *testing.T) {
+		runner := goservices.NewRunner()
+		app := New().
+			WithRunner(runner).
+			WithSkipConfig(true).
+			WithDisableSystemServer(true)
+
+		err := app.run(func(ctx context.Context, app *Application) ([]goservices.Service, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		assert.Same(t, runner, app.Runner)
+	})
+
+	t.Run("should attach the observers to the given runner", func(t *testing.T) {
+		app := New().
+			WithRunner(goservices.NewRunner()).
+			WithSkipConfig(true)
+
+		// Running twice checks the observers of the second run are attached to the runner.
+		for i := 0; i < 2; i++ {
+			ctx, cancelFunc := context.WithCancel(context.Background())
+			app.WithContext(ctx)
+
+			done := make(chan error, 1)
+			go func() {
+				done <- app.RunE(func(ctx context.Context, app *Application) ([]goservices.Service, error) {
+					return []goservices.Service{&notReadyResource{err: errors.New("still connecting")}}, nil
+				})
+			}()
+
+			require.Eventually(t, func() bool {
+				readyz, err := getReadyz()
+				return err == nil && readyz.Checks["not ready"].Error == "still connecting"
+			}, time.Second*5, time.Millisecond*50, "the ready check of the service should be registered")
+
+			cancelFunc()
+			require.NoError(t, <-done)
+		}
+	})
+}
+
 func TestApplication(t *testing.T) {
 	t.Run("should start and stop all servers and resources", func(t *testing.T) {
 		ctx, cancelFunc := context.WithCancel(context.Background())
@@ -0,0 +1,166 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/jamillosantos/config"
+	goenv "github.com/jamillosantos/go-env"
+	"github.com/jamillosantos/logctx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	ErrConfigNotLoaded      = errors.New("config not loaded")
+	ErrInvalidConfigTarget  = errors.New("config target must be a pointer to a struct")
+	ErrInvalidLogSampling   = errors.New("invalid log sampling")
+	ErrEmptyEnvConfigPrefix = errors.New("env config prefix must not be empty")
+)
+
+// loadConfig initializes and loads the flag, env, plain and secret configuration engines, publishing the config manager to be
+// used into the setup callback.
+func (app *Application) loadConfig() error {
+	var plain, secret config.Engine
+	plain = config.NewYAMLEngine(config.NewFileLoader(goenv.GetStringDefault("CONFIG", ".config.yaml")))
+	secret = config.NewYAMLEngine(config.NewFileLoader(goenv.GetStringDefault("SECRETS", ".secrets.yaml")))
+
+	// The precedence order is: flags > env > secret/plain files. Each layer falls back to the next one when a key is not
+	// set.
+	if app.configEnv {
+		plain = newEnvEngine(app.configEnvPrefix, plain)
+		secret = newEnvEngine(app.configEnvPrefix, secret)
+	}
+	if app.configFlagSet != nil {
+		plain = newFlagEngine(app.configFlagSet, plain)
+		secret = newFlagEngine(app.configFlagSet, secret)
+	}
+
+	// Guards the engines so the configuration can be reloaded while it is being read.
+	plain, secret = newLockedEngine(plain), newLockedEngine(secret)
+
+	// Loading the top engine loads every layer below it.
+	if err := plain.Load(); err != nil {
+		return fmt.Errorf("could not initialize the plain configuration: %w", err)
+	}
+	if err := secret.Load(); err != nil {
+		return fmt.Errorf("could not initialize the secret configuration: %w", err)
+	}
+
+	configManager := config.NewManager()
+	configManager.AddPlainEngine(plain)
+	configManager.AddSecretEngine(secret)
+
+	app.configEngines = []config.Engine{plain, secret}
+	app.ConfigManager = configManager
+	return nil
+}
+
+// Reload reloads the configuration engines and re-applies the reloadable settings to the running application, without
+// restarting the services.
+//
+// The reloadable log settings are:
+//   - log.level: the minimum enabled level (debug, info, warn, error, dpanic, panic or fatal).
+//   - log.sampling.initial and log.sampling.thereafter: per second, the first `initial` entries with the same level and
+//     message are logged, and then every `thereafter`th entry. The sampling is only changed when log.sampling.initial
+//     is set.
+//
+// Any other logger setting (encoding, outputs) is defined when the logger is built and requires a restart.
+//
+// Reload is safe to call while the configuration is being read (ConfigManager.Populate, UnmarshalConfig).
+func (app *Application) Reload(ctx context.Context) error {
+	if app.ConfigManager == nil {
+		return ErrConfigNotLoaded
+	}
+	for _, engine := range app.configEngines {
+		if err := engine.Load(); err != nil {
+			return fmt.Errorf("could not reload the configuration: %w", err)
+		}
+	}
+	logctx.From(ctx).Info("configuration reloaded")
+	return app.applyLogConfig(ctx)
+}
+
+// UnmarshalConfig decodes the configuration under the given key into out, which must be a pointer to a struct with
+// `config` tags. It allows each service to read its own typed config:
+//
+//	var redisCfg RedisConfig
+//	err := app.UnmarshalConfig("redis", &redisCfg)
+//
+// Nested keys are separated by "." (eg: "storage.redis"). An empty key decodes the whole configuration. Fields that are
+// not found keep their values.
+func (app *Application) UnmarshalConfig(key string, out interface{}) error {
+	if app.ConfigManager == nil {
+		return ErrConfigNotLoaded
+	}
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T given", ErrInvalidConfigTarget, out)
+	}
+	if key == "" {
+		return app.ConfigManager.Populate(out)
+	}
+
+	// The config.Manager only populates from the root. So, out is wrapped into a struct that places it under the key.
+	wrapper := reflect.New(reflect.StructOf([]reflect.StructField{{
+		Name: "Value",
+		Type: v.Elem().Type(),
+		Tag:  reflect.StructTag(fmt.Sprintf("config:%q", key)),
+	}}))
+	wrapper.Elem().Field(0).Set(v.Elem())
+	if err := app.ConfigManager.Populate(wrapper.Interface()); err != nil {
+		return fmt.Errorf("failed unmarshaling config %s: %w", key, err)
+	}
+	v.Elem().Set(wrapper.Elem().Field(0))
+	return nil
+}
+
+// logConfig holds the reloadable log settings read from the configuration.
+type logConfig struct {
+	Log struct {
+		Level    string `config:"level"`
+		Sampling struct {
+			Initial    int `config:"initial"`
+			Thereafter int `config:"thereafter"`
+		} `config:"sampling"`
+	} `config:"log"`
+}
+
+// applyLogConfig reads the log settings from the configuration and updates the live logger in place.
+func (app *Application) applyLogConfig(ctx context.Context) error {
+	var cfg logConfig
+	if err := app.ConfigManager.Populate(&cfg); err != nil {
+		return err
+	}
+	if cfg.Log.Level != "" {
+		level, err := zapcore.ParseLevel(cfg.Log.Level)
+		if err != nil {
+			return err
+		}
+		if level != app.loggerLevel.Level() {
+			app.loggerLevel.SetLevel(level)
+			logctx.From(ctx).Info("log level changed", zap.String("level", level.String()))
+		}
+	}
+	if cfg.Log.Sampling.Initial > 0 {
+		if cfg.Log.Sampling.Thereafter < 0 {
+			return fmt.Errorf("%w: log.sampling.thereafter must not be negative", ErrInvalidLogSampling)
+		}
+		params := &logSamplingParams{
+			tick:       time.Second,
+			initial:    uint64(cfg.Log.Sampling.Initial),
+			thereafter: uint64(cfg.Log.Sampling.Thereafter),
+		}
+		if current := app.loggerSampler.params.Load(); current == nil || *current != *params {
+			app.loggerSampler.set(params)
+			logctx.From(ctx).Info("log sampling changed",
+				zap.Int("initial", cfg.Log.Sampling.Initial),
+				zap.Int("thereafter", cfg.Log.Sampling.Thereafter),
+			)
+		}
+	}
+	return nil
+}
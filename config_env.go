@@ -0,0 +1,36 @@
+package application
+
+import (
+	"os"
+	"strings"
+
+	"github.com/jamillosantos/config"
+)
+
+// envSource is a configSource that reads the environment variables.
+//
+// The configuration key is mapped to the variable name by replacing "." and "-" with "_", upper casing it and adding
+// the prefix. Hence, with the "MYAPP" prefix, the `db.host` key is read from `MYAPP_DB_HOST`.
+type envSource struct {
+	prefix string
+}
+
+func newEnvEngine(prefix string, fallback config.Engine) *overrideEngine {
+	return newOverrideEngine(&envSource{prefix: prefix}, fallback)
+}
+
+func (source *envSource) name() string {
+	return "env"
+}
+
+// load does nothing, since the variables are read when looked up.
+func (source *envSource) load() {}
+
+func (source *envSource) lookup(key string) (string, bool) {
+	return os.LookupEnv(source.variable(key))
+}
+
+// variable returns the name of the environment variable of the key.
+func (source *envSource) variable(key string) string {
+	return source.prefix + "_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
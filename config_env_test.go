@@ -0,0 +1,68 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/jamillosantos/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvEngine(t *testing.T) {
+	t.Setenv("APP_DB_HOST", "env-host")
+	t.Setenv("APP_DB_READ_TIMEOUT", "2s")
+	t.Setenv("APP_HOSTS", "a, b")
+	t.Setenv("APP_PORT", "not a number")
+
+	engine := newEnvEngine("APP", config.NewMapEngine(map[string]interface{}{
+		"db": map[string]interface{}{
+			"host": "fallback",
+			"name": "fallback",
+		},
+	}))
+	require.NoError(t, engine.Load())
+
+	host, err := engine.GetString("db.host")
+	require.NoError(t, err)
+	assert.Equal(t, "env-host", host)
+
+	name, err := engine.GetString("db.name")
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", name)
+
+	timeout, err := engine.GetString("db.read-timeout")
+	require.NoError(t, err)
+	assert.Equal(t, "2s", timeout)
+
+	hosts, err := engine.GetStringSlice("hosts")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, hosts)
+
+	_, err = engine.GetInt("port")
+	assert.ErrorIs(t, err, config.ErrTypeMismatch)
+
+	_, err = engine.GetString("missing")
+	assert.ErrorIs(t, err, config.ErrKeyNotFound)
+}
+
+func TestApplication_WithEnvConfig(t *testing.T) {
+	t.Run("should override the files without flags", func(t *testing.T) {
+		writeConfigFiles(t, "db:\n  host: file-host\n  port: 5432\n", "db:\n  password: file-password\n")
+		t.Setenv("TEST_DB_HOST", "env-host")
+		t.Setenv("TEST_DB_PASSWORD", "env-password")
+		app := New().WithEnvConfig("TEST")
+		require.NoError(t, app.loadConfig())
+
+		var cfg flagTestConfig
+		require.NoError(t, app.ConfigManager.Populate(&cfg))
+		assert.Equal(t, "env-host", cfg.DB.Host)
+		assert.Equal(t, 5432, cfg.DB.Port)
+		assert.Equal(t, "env-password", cfg.DB.Password)
+	})
+
+	t.Run("should panic given an empty prefix", func(t *testing.T) {
+		assert.PanicsWithError(t, ErrEmptyEnvConfigPrefix.Error(), func() {
+			New().WithEnvConfig("")
+		})
+	})
+}
@@ -0,0 +1,39 @@
+package application
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/jamillosantos/config"
+)
+
+// flagSource is a configSource that reads the flags of a parsed flag.FlagSet.
+//
+// Only flags explicitly set in the command line are taken into account, so the flag defaults never shadow the values
+// from the fallback engine. The flag name is mapped to the configuration key by replacing "-" with ".". Hence,
+// `--db-host` is read as the `db.host` key.
+type flagSource struct {
+	flagSet *flag.FlagSet
+	data    map[string]string
+}
+
+func newFlagEngine(flagSet *flag.FlagSet, fallback config.Engine) *overrideEngine {
+	return newOverrideEngine(&flagSource{flagSet: flagSet}, fallback)
+}
+
+func (source *flagSource) name() string {
+	return "flag"
+}
+
+func (source *flagSource) load() {
+	data := make(map[string]string)
+	source.flagSet.Visit(func(f *flag.Flag) {
+		data[strings.ReplaceAll(f.Name, "-", ".")] = f.Value.String()
+	})
+	source.data = data
+}
+
+func (source *flagSource) lookup(key string) (string, bool) {
+	value, ok := source.data[key]
+	return value, ok
+}
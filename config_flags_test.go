@@ -0,0 +1,127 @@
+package application
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jamillosantos/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type flagTestConfig struct {
+	DB struct {
+		Host     string        `config:"host"`
+		Port     int           `config:"port"`
+		Timeout  time.Duration `config:"timeout"`
+		Password string        `config:"password,secret"`
+	} `config:"db"`
+}
+
+func writeConfigFiles(t *testing.T, plain, secrets string) {
+	t.Helper()
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".config.yaml")
+	secretsFile := filepath.Join(dir, ".secrets.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(plain), 0o600))
+	require.NoError(t, os.WriteFile(secretsFile, []byte(secrets), 0o600))
+	t.Setenv("CONFIG", configFile)
+	t.Setenv("SECRETS", secretsFile)
+}
+
+func newTestFlagSet(t *testing.T, args ...string) *flag.FlagSet {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("db-host", "default-host", "")
+	fs.Int("db-port", 1, "")
+	fs.Duration("db-timeout", time.Second, "")
+	fs.String("db-password", "", "")
+	require.NoError(t, fs.Parse(args))
+	return fs
+}
+
+func TestApplication_WithFlagConfig(t *testing.T) {
+	t.Run("should give flags precedence over plain and secret files", func(t *testing.T) {
+		writeConfigFiles(t,
+			"db:\n  host: file-host\n  port: 5432\n  timeout: 5s\n",
+			"db:\n  password: file-password\n",
+		)
+		app := New().WithFlagConfig(newTestFlagSet(t, "--db-host=flag-host", "--db-password=flag-password"))
+		require.NoError(t, app.loadConfig())
+
+		var cfg flagTestConfig
+		require.NoError(t, app.ConfigManager.Populate(&cfg))
+		assert.Equal(t, "flag-host", cfg.DB.Host)
+		assert.Equal(t, "flag-password", cfg.DB.Password)
+		// Flags not set in the command line do not override the files.
+		assert.Equal(t, 5432, cfg.DB.Port)
+		assert.Equal(t, time.Second*5, cfg.DB.Timeout)
+	})
+
+	t.Run("should honor the precedence flags > env > secret/plain files", func(t *testing.T) {
+		writeConfigFiles(t,
+			"db:\n  host: file-host\n  port: 5432\n  timeout: 5s\n",
+			"db:\n  password: file-password\n",
+		)
+		t.Setenv("TEST_DB_HOST", "env-host")
+		t.Setenv("TEST_DB_PORT", "6543")
+		t.Setenv("TEST_DB_PASSWORD", "env-password")
+		app := New().
+			WithEnvConfig("TEST").
+			WithFlagConfig(newTestFlagSet(t, "--db-host=flag-host"))
+		require.NoError(t, app.loadConfig())
+
+		var cfg flagTestConfig
+		require.NoError(t, app.ConfigManager.Populate(&cfg))
+		assert.Equal(t, "flag-host", cfg.DB.Host)
+		assert.Equal(t, 6543, cfg.DB.Port)
+		assert.Equal(t, "env-password", cfg.DB.Password)
+		assert.Equal(t, time.Second*5, cfg.DB.Timeout)
+	})
+
+	t.Run("should parse typed values from flags", func(t *testing.T) {
+		writeConfigFiles(t, "db:\n  host: file-host\n", "s: 1\n")
+		app := New().WithFlagConfig(newTestFlagSet(t, "--db-port=6543", "--db-timeout=3s"))
+		require.NoError(t, app.loadConfig())
+
+		var cfg flagTestConfig
+		require.NoError(t, app.ConfigManager.Populate(&cfg))
+		assert.Equal(t, "file-host", cfg.DB.Host)
+		assert.Equal(t, 6543, cfg.DB.Port)
+		assert.Equal(t, time.Second*3, cfg.DB.Timeout)
+	})
+}
+
+func TestFlagEngine(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("hosts", "", "")
+	fs.String("name", "", "")
+	require.NoError(t, fs.Parse([]string{"--hosts=a, b", "--name=value"}))
+
+	engine := newFlagEngine(fs, config.NewMapEngine(map[string]interface{}{
+		"name":  "fallback",
+		"other": "fallback",
+	}))
+	require.NoError(t, engine.Load())
+
+	name, err := engine.GetString("name")
+	require.NoError(t, err)
+	assert.Equal(t, "value", name)
+
+	other, err := engine.GetString("other")
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", other)
+
+	hosts, err := engine.GetStringSlice("hosts")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, hosts)
+
+	_, err = engine.GetInt("name")
+	assert.ErrorIs(t, err, config.ErrTypeMismatch)
+
+	_, err = engine.GetString("missing")
+	assert.ErrorIs(t, err, config.ErrKeyNotFound)
+}
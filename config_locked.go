@@ -0,0 +1,100 @@
+package application
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jamillosantos/config"
+)
+
+// lockedEngine is a config.Engine that guards another engine with a sync.RWMutex, so the configuration can be reloaded
+// (Load) while it is being read.
+type lockedEngine struct {
+	m      sync.RWMutex
+	engine config.Engine
+}
+
+func newLockedEngine(engine config.Engine) *lockedEngine {
+	return &lockedEngine{
+		engine: engine,
+	}
+}
+
+func (engine *lockedEngine) Load() error {
+	engine.m.Lock()
+	defer engine.m.Unlock()
+	return engine.engine.Load()
+}
+
+func (engine *lockedEngine) Unload() error {
+	engine.m.Lock()
+	defer engine.m.Unlock()
+	return engine.engine.Unload()
+}
+
+// lockedGet reads the key holding the read lock of the engine.
+func lockedGet[T any](engine *lockedEngine, key string, get func(string) (T, error)) (T, error) {
+	engine.m.RLock()
+	defer engine.m.RUnlock()
+	return get(key)
+}
+
+func (engine *lockedEngine) GetString(key string) (string, error) {
+	return lockedGet(engine, key, engine.engine.GetString)
+}
+
+func (engine *lockedEngine) GetStringSlice(key string) ([]string, error) {
+	return lockedGet(engine, key, engine.engine.GetStringSlice)
+}
+
+func (engine *lockedEngine) GetInt(key string) (int, error) {
+	return lockedGet(engine, key, engine.engine.GetInt)
+}
+
+func (engine *lockedEngine) GetIntSlice(key string) ([]int, error) {
+	return lockedGet(engine, key, engine.engine.GetIntSlice)
+}
+
+func (engine *lockedEngine) GetUint(key string) (uint, error) {
+	return lockedGet(engine, key, engine.engine.GetUint)
+}
+
+func (engine *lockedEngine) GetUintSlice(key string) ([]uint, error) {
+	return lockedGet(engine, key, engine.engine.GetUintSlice)
+}
+
+func (engine *lockedEngine) GetInt64(key string) (int64, error) {
+	return lockedGet(engine, key, engine.engine.GetInt64)
+}
+
+func (engine *lockedEngine) GetInt64Slice(key string) ([]int64, error) {
+	return lockedGet(engine, key, engine.engine.GetInt64Slice)
+}
+
+func (engine *lockedEngine) GetUint64(key string) (uint64, error) {
+	return lockedGet(engine, key, engine.engine.GetUint64)
+}
+
+func (engine *lockedEngine) GetUint64Slice(key string) ([]uint64, error) {
+	return lockedGet(engine, key, engine.engine.GetUint64Slice)
+}
+
+func (engine *lockedEngine) GetBool(key string) (bool, error) {
+	return lockedGet(engine, key, engine.engine.GetBool)
+}
+
+func (engine *lockedEngine) GetBoolSlice(key string) ([]bool, error) {
+	return lockedGet(engine, key, engine.engine.GetBoolSlice)
+}
+
+func (engine *lockedEngine) GetFloat(key string) (float64, error) {
+	return lockedGet(engine, key, engine.engine.GetFloat)
+}
+
+func (engine *lockedEngine) GetFloatSlice(key string) ([]float64, error) {
+	return lockedGet(engine, key, engine.engine.GetFloatSlice)
+}
+
+func (engine *lockedEngine) GetDuration(key string) (time.Duration, error) {
+	return lockedGet(engine, key, engine.engine.GetDuration)
+}
@@ -0,0 +1,162 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jamillosantos/config"
+)
+
+// configSource is a source of configuration values that override the configuration files, such as flags or
+// environment variables.
+type configSource interface {
+	// name identifies the source in the error messages.
+	name() string
+	// load (re)reads the values of the source.
+	load()
+	// lookup returns the value of the key and true, if the key is set in the source.
+	lookup(key string) (string, bool)
+}
+
+// overrideEngine is a config.Engine that reads the configuration from a configSource, falling back to another engine
+// when the key is not set in the source. Slices are given as comma separated values.
+type overrideEngine struct {
+	source   configSource
+	fallback config.Engine
+}
+
+func newOverrideEngine(source configSource, fallback config.Engine) *overrideEngine {
+	return &overrideEngine{
+		source:   source,
+		fallback: fallback,
+	}
+}
+
+// Load reads the values of the source and loads the fallback engine.
+func (engine *overrideEngine) Load() error {
+	engine.source.load()
+	return engine.fallback.Load()
+}
+
+func (engine *overrideEngine) Unload() error {
+	return engine.fallback.Unload()
+}
+
+// getValue parses the value of the key, returning a config.ErrTypeMismatch if it cannot be parsed. If the key is not
+// set in the source, the value is read from the fallback engine.
+func getValue[T any](engine *overrideEngine, key string, parse func(string) (T, error), fallback func(string) (T, error)) (T, error) {
+	value, ok := engine.source.lookup(key)
+	if !ok {
+		return fallback(key)
+	}
+	r, err := parse(value)
+	if err != nil {
+		return r, fmt.Errorf("%w: %s %s: %s", config.ErrTypeMismatch, engine.source.name(), key, err)
+	}
+	return r, nil
+}
+
+// getSlice parses the value of the key as a comma separated list, trimming the spaces around each item. If the key is
+// not set in the source, the value is read from the fallback engine.
+func getSlice[T any](engine *overrideEngine, key string, parse func(string) (T, error), fallback func(string) ([]T, error)) ([]T, error) {
+	value, ok := engine.source.lookup(key)
+	if !ok {
+		return fallback(key)
+	}
+	if value == "" {
+		return []T{}, nil
+	}
+	values := strings.Split(value, ",")
+	r := make([]T, len(values))
+	for i, value := range values {
+		v, err := parse(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %s: %s", config.ErrTypeMismatch, engine.source.name(), key, err)
+		}
+		r[i] = v
+	}
+	return r, nil
+}
+
+func (engine *overrideEngine) GetString(key string) (string, error) {
+	return getValue(engine, key, parseString, engine.fallback.GetString)
+}
+
+func (engine *overrideEngine) GetStringSlice(key string) ([]string, error) {
+	return getSlice(engine, key, parseString, engine.fallback.GetStringSlice)
+}
+
+func (engine *overrideEngine) GetInt(key string) (int, error) {
+	return getValue(engine, key, strconv.Atoi, engine.fallback.GetInt)
+}
+
+func (engine *overrideEngine) GetIntSlice(key string) ([]int, error) {
+	return getSlice(engine, key, strconv.Atoi, engine.fallback.GetIntSlice)
+}
+
+func (engine *overrideEngine) GetUint(key string) (uint, error) {
+	return getValue(engine, key, parseUint, engine.fallback.GetUint)
+}
+
+func (engine *overrideEngine) GetUintSlice(key string) ([]uint, error) {
+	return getSlice(engine, key, parseUint, engine.fallback.GetUintSlice)
+}
+
+func (engine *overrideEngine) GetInt64(key string) (int64, error) {
+	return getValue(engine, key, parseInt64, engine.fallback.GetInt64)
+}
+
+func (engine *overrideEngine) GetInt64Slice(key string) ([]int64, error) {
+	return getSlice(engine, key, parseInt64, engine.fallback.GetInt64Slice)
+}
+
+func (engine *overrideEngine) GetUint64(key string) (uint64, error) {
+	return getValue(engine, key, parseUint64, engine.fallback.GetUint64)
+}
+
+func (engine *overrideEngine) GetUint64Slice(key string) ([]uint64, error) {
+	return getSlice(engine, key, parseUint64, engine.fallback.GetUint64Slice)
+}
+
+func (engine *overrideEngine) GetBool(key string) (bool, error) {
+	return getValue(engine, key, strconv.ParseBool, engine.fallback.GetBool)
+}
+
+func (engine *overrideEngine) GetBoolSlice(key string) ([]bool, error) {
+	return getSlice(engine, key, strconv.ParseBool, engine.fallback.GetBoolSlice)
+}
+
+func (engine *overrideEngine) GetFloat(key string) (float64, error) {
+	return getValue(engine, key, parseFloat64, engine.fallback.GetFloat)
+}
+
+func (engine *overrideEngine) GetFloatSlice(key string) ([]float64, error) {
+	return getSlice(engine, key, parseFloat64, engine.fallback.GetFloatSlice)
+}
+
+func (engine *overrideEngine) GetDuration(key string) (time.Duration, error) {
+	return getValue(engine, key, time.ParseDuration, engine.fallback.GetDuration)
+}
+
+func parseString(s string) (string, error) {
+	return s, nil
+}
+
+func parseUint(s string) (uint, error) {
+	v, err := strconv.ParseUint(s, 10, 0)
+	return uint(v), err
+}
+
+func parseInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+func parseUint64(s string) (uint64, error) {
+	return strconv.ParseUint(s, 10, 64)
+}
+
+func parseFloat64(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
@@ -0,0 +1,175 @@
+package application
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jamillosantos/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestApplication_Reload(t *testing.T) {
+	t.Run("should update the log level in place", func(t *testing.T) {
+		dir := t.TempDir()
+		configFile := filepath.Join(dir, ".config.yaml")
+		secretsFile := filepath.Join(dir, ".secrets.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte("log:\n  level: warn\n"), 0o600))
+		require.NoError(t, os.WriteFile(secretsFile, []byte("s: 1\n"), 0o600))
+		t.Setenv("CONFIG", configFile)
+		t.Setenv("SECRETS", secretsFile)
+
+		app := New().WithEnvironment("production")
+		_, err := app.buildLogger()
+		require.NoError(t, err)
+		require.Equal(t, zapcore.InfoLevel, app.loggerLevel.Level())
+
+		require.NoError(t, app.loadConfig())
+		require.NoError(t, app.applyLogConfig(context.Background()))
+		assert.Equal(t, zapcore.WarnLevel, app.loggerLevel.Level())
+
+		require.NoError(t, os.WriteFile(configFile, []byte("log:\n  level: debug\n"), 0o600))
+		require.NoError(t, app.Reload(context.Background()))
+		assert.Equal(t, zapcore.DebugLevel, app.loggerLevel.Level())
+	})
+
+	t.Run("should update the log sampling in place", func(t *testing.T) {
+		writeConfigFiles(t, "log:\n  sampling:\n    initial: 10\n    thereafter: 50\n", "s: 1\n")
+
+		app := New().WithEnvironment("production")
+		_, err := app.buildLogger()
+		require.NoError(t, err)
+		require.Equal(t, logSamplingParams{tick: time.Second, initial: 100, thereafter: 100}, *app.loggerSampler.params.Load())
+
+		require.NoError(t, app.loadConfig())
+		require.NoError(t, app.Reload(context.Background()))
+		assert.Equal(t, logSamplingParams{tick: time.Second, initial: 10, thereafter: 50}, *app.loggerSampler.params.Load())
+	})
+
+	t.Run("should enable the log sampling", func(t *testing.T) {
+		writeConfigFiles(t, "log:\n  sampling:\n    initial: 1\n", "s: 1\n")
+
+		app := New().WithEnvironment("dev")
+		_, err := app.buildLogger()
+		require.NoError(t, err)
+		require.Nil(t, app.loggerSampler.params.Load())
+
+		require.NoError(t, app.loadConfig())
+		require.NoError(t, app.Reload(context.Background()))
+		assert.Equal(t, logSamplingParams{tick: time.Second, initial: 1}, *app.loggerSampler.params.Load())
+	})
+
+	t.Run("should fail with a negative log.sampling.thereafter", func(t *testing.T) {
+		writeConfigFiles(t, "log:\n  sampling:\n    initial: 1\n    thereafter: -1\n", "s: 1\n")
+
+		app := New()
+		_, err := app.buildLogger()
+		require.NoError(t, err)
+		require.NoError(t, app.loadConfig())
+		assert.ErrorIs(t, app.Reload(context.Background()), ErrInvalidLogSampling)
+	})
+
+	t.Run("should reload while the configuration is being read", func(t *testing.T) {
+		writeConfigFiles(t, "log:\n  level: info\nredis:\n  host: localhost\n", "s: 1\n")
+
+		app := New()
+		_, err := app.buildLogger()
+		require.NoError(t, err)
+		require.NoError(t, app.loadConfig())
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 50; i++ {
+				assert.NoError(t, app.Reload(context.Background()))
+			}
+		}()
+		for i := 0; i < 50; i++ {
+			var cfg redisTestConfig
+			require.NoError(t, app.UnmarshalConfig("redis", &cfg))
+			assert.Equal(t, "localhost", cfg.Host)
+		}
+		<-done
+	})
+
+	t.Run("should fail when the configuration was not loaded", func(t *testing.T) {
+		app := New()
+		assert.ErrorIs(t, app.Reload(context.Background()), ErrConfigNotLoaded)
+	})
+}
+
+type redisTestConfig struct {
+	Host    string        `config:"host"`
+	Port    int           `config:"port"`
+	Timeout time.Duration `config:"timeout"`
+}
+
+func TestApplication_UnmarshalConfig(t *testing.T) {
+	newApp := func(data map[string]interface{}) *Application {
+		manager := config.NewManager()
+		manager.AddPlainEngine(config.NewMapEngine(data))
+		manager.AddSecretEngine(config.NewMapEngine(map[string]interface{}{}))
+		app := New()
+		app.ConfigManager = manager
+		return app
+	}
+
+	t.Run("should decode the subtree into the struct", func(t *testing.T) {
+		app := newApp(map[string]interface{}{
+			"storage": map[string]interface{}{
+				"redis": map[string]interface{}{
+					"host":    "localhost",
+					"port":    6379,
+					"timeout": "2s",
+				},
+			},
+		})
+
+		cfg := redisTestConfig{Host: "default"}
+		require.NoError(t, app.UnmarshalConfig("storage.redis", &cfg))
+		assert.Equal(t, redisTestConfig{Host: "localhost", Port: 6379, Timeout: time.Second * 2}, cfg)
+	})
+
+	t.Run("should keep the values of fields not found", func(t *testing.T) {
+		app := newApp(map[string]interface{}{
+			"redis": map[string]interface{}{
+				"port": 6379,
+			},
+		})
+
+		cfg := redisTestConfig{Host: "default"}
+		require.NoError(t, app.UnmarshalConfig("redis", &cfg))
+		assert.Equal(t, "default", cfg.Host)
+		assert.Equal(t, 6379, cfg.Port)
+	})
+
+	t.Run("should fail on type mismatch", func(t *testing.T) {
+		app := newApp(map[string]interface{}{
+			"redis": map[string]interface{}{
+				"port": "not a number",
+			},
+		})
+
+		var cfg redisTestConfig
+		err := app.UnmarshalConfig("redis", &cfg)
+		assert.ErrorIs(t, err, config.ErrTypeMismatch)
+		assert.Contains(t, err.Error(), "redis")
+	})
+
+	t.Run("should fail when the target is not a pointer to a struct", func(t *testing.T) {
+		app := newApp(map[string]interface{}{})
+
+		var port int
+		assert.ErrorIs(t, app.UnmarshalConfig("redis", &port), ErrInvalidConfigTarget)
+		assert.ErrorIs(t, app.UnmarshalConfig("redis", redisTestConfig{}), ErrInvalidConfigTarget)
+	})
+
+	t.Run("should fail when the configuration was not loaded", func(t *testing.T) {
+		var cfg redisTestConfig
+		assert.ErrorIs(t, New().UnmarshalConfig("redis", &cfg), ErrConfigNotLoaded)
+	})
+}
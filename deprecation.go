@@ -0,0 +1,23 @@
+package application
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// withVersionDeprecationOnce ensures the WithVersion deprecation is logged once per process. It is a variable so the
+// tests can reset it.
+var withVersionDeprecationOnce sync.Once
+
+// warnDeprecations logs the usage of deprecated features, nudging the migration without breaking the callers.
+func (app *Application) warnDeprecations(logger *zap.Logger) {
+	if app.withVersionUsed {
+		withVersionDeprecationOnce.Do(func() {
+			logger.Warn("deprecated feature used",
+				zap.String("deprecated", "Application.WithVersion"),
+				zap.String("replacement", "go build info (runtime/debug.ReadBuildInfo)"),
+			)
+		})
+	}
+}
@@ -91,3 +91,32 @@ func (r *dummyResource) Stop(ctx context.Context) error {
 	r.started = false
 	return nil
 }
+
+type failingResource struct {
+	err error
+}
+
+func (r *failingResource) Name() string {
+	return "failing"
+}
+
+func (r *failingResource) Start(ctx context.Context) error {
+	return r.err
+}
+
+func (r *failingResource) Stop(ctx context.Context) error {
+	return nil
+}
+
+type notReadyResource struct {
+	dummyResource
+	err error
+}
+
+func (r *notReadyResource) Name() string {
+	return "not ready"
+}
+
+func (r *notReadyResource) IsReady(_ context.Context) error {
+	return r.err
+}
@@ -13,6 +13,9 @@ type ReadyChecker interface {
 	IsReady(ctx context.Context) error
 }
 
+// appCheckName is the name of the ready check that reports the application state.
+const appCheckName = "app"
+
 type appChecker struct {
 	*Application
 }
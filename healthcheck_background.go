@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// backgroundChecker is a goservices.Server that periodically runs the checks. It either feeds the cache used by the
+// health and ready endpoints (readinessAggregator.refresh) or only the unhealthy monitor
+// (readinessAggregator.observeUnhealthy).
+type backgroundChecker struct {
+	name     string
+	check    func(ctx context.Context)
+	interval time.Duration
+
+	cancelFunc context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+func newBackgroundChecker(name string, interval time.Duration, check func(ctx context.Context)) *backgroundChecker {
+	return &backgroundChecker{
+		name:     name,
+		check:    check,
+		interval: interval,
+	}
+}
+
+func (c *backgroundChecker) Name() string {
+	return c.name
+}
+
+// Listen starts running the checks in the background, the first time right away. It does not block.
+func (c *backgroundChecker) Listen(ctx context.Context) error {
+	ctx, c.cancelFunc = context.WithCancel(ctx)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.check(ctx)
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.check(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+func (c *backgroundChecker) Close(_ context.Context) error {
+	if c.cancelFunc != nil {
+		c.cancelFunc()
+	}
+	c.wg.Wait()
+	return nil
+}
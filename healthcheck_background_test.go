@@ -0,0 +1,104 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	svchealthcheck "github.com/jamillosantos/services-healthcheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackgroundChecker(t *testing.T) {
+	var calls int32
+	hc := svchealthcheck.NewHealthcheck(
+		svchealthcheck.WithReadyCheck("db", svchealthcheck.CheckerFunc(func(context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		})),
+	)
+	readiness := newReadinessAggregator(hc, newHealthchekcObserver(hc, 0, nil), nil)
+	checker := newBackgroundChecker("background checks", time.Millisecond*20, readiness.refresh)
+
+	require.NoError(t, checker.Listen(context.Background()))
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) >= 3
+	}, time.Second, time.Millisecond*10)
+
+	require.NoError(t, checker.Close(context.Background()))
+	stoppedCalls := atomic.LoadInt32(&calls)
+
+	// The endpoints are served from the cache, not running the checks.
+	resp := readiness.Ready(context.Background())
+	assert.Contains(t, resp.Checks, "db")
+	time.Sleep(time.Millisecond * 50)
+	assert.Equal(t, stoppedCalls, atomic.LoadInt32(&calls))
+}
+
+func TestBackgroundChecker_Listen(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	hc := svchealthcheck.NewHealthcheck(
+		svchealthcheck.WithReadyCheck("db", svchealthcheck.CheckerFunc(func(context.Context) error {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+			return nil
+		})),
+	)
+	readiness := newReadinessAggregator(hc, newHealthchekcObserver(hc, 0, nil), nil)
+	checker := newBackgroundChecker("background checks", time.Hour, readiness.refresh)
+
+	// Listen must not block, even while the checks are running.
+	require.NoError(t, checker.Listen(context.Background()))
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("should run the checks right away")
+	}
+	close(release)
+	require.NoError(t, checker.Close(context.Background()))
+}
+
+func TestReadinessAggregator_refresh(t *testing.T) {
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	hc := svchealthcheck.NewHealthcheck(
+		svchealthcheck.WithReadyCheck(appCheckName, svchealthcheck.CheckerFunc(func(context.Context) error {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				// The first run reads the state before the application is running, and finishes late.
+				close(started)
+				<-release
+				return ErrAppNotRunningYet
+			}
+			return nil
+		})),
+	)
+	readiness := newReadinessAggregator(hc, newHealthchekcObserver(hc, 0, nil), nil)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		readiness.refresh(context.Background())
+	}()
+	<-started
+	go func() {
+		defer wg.Done()
+		readiness.refresh(context.Background())
+	}()
+	time.Sleep(time.Millisecond * 20)
+	close(release)
+	wg.Wait()
+
+	resp := readiness.Ready(context.Background())
+	assert.Empty(t, resp.Checks[appCheckName].Error, "the older run should not overwrite the newer results")
+}
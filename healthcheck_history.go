@@ -0,0 +1,48 @@
+package application
+
+import (
+	"sync"
+	"time"
+)
+
+// CheckError is a failure of a check kept in the recent errors history.
+type CheckError struct {
+	Error string    `json:"error"`
+	Time  time.Time `json:"time"`
+}
+
+// errorHistory is a bounded ring buffer keeping the most recent errors of a check.
+type errorHistory struct {
+	m       sync.Mutex
+	entries []CheckError
+	next    int
+	full    bool
+}
+
+func newErrorHistory(size int) *errorHistory {
+	return &errorHistory{
+		entries: make([]CheckError, size),
+	}
+}
+
+func (h *errorHistory) add(entry CheckError) {
+	h.m.Lock()
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+	h.m.Unlock()
+}
+
+// list returns a copy of the errors, from the oldest to the most recent.
+func (h *errorHistory) list() []CheckError {
+	h.m.Lock()
+	defer h.m.Unlock()
+	if !h.full {
+		return append([]CheckError{}, h.entries[:h.next]...)
+	}
+	r := make([]CheckError, 0, len(h.entries))
+	r = append(r, h.entries[h.next:]...)
+	return append(r, h.entries[:h.next]...)
+}
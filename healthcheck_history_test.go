@@ -0,0 +1,95 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	goservices "github.com/jamillosantos/go-services"
+	svchealthcheck "github.com/jamillosantos/services-healthcheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorHistory(t *testing.T) {
+	h := newErrorHistory(3)
+	assert.Empty(t, h.list())
+
+	for _, msg := range []string{"1", "2", "3", "4", "5"} {
+		h.add(CheckError{Error: msg})
+	}
+
+	got := h.list()
+	require.Len(t, got, 3)
+	assert.Equal(t, "3", got[0].Error)
+	assert.Equal(t, "4", got[1].Error)
+	assert.Equal(t, "5", got[2].Error)
+}
+
+func TestReadinessAggregator_RecentErrors(t *testing.T) {
+	hc := svchealthcheck.NewHealthcheck()
+	observer := newHealthchekcObserver(hc, 2, func(message string) string {
+		return strings.ReplaceAll(message, "s3cr3t", "***")
+	})
+	observer.addReadyCheck("db", readyChecker(errors.New("connecting with password s3cr3t")))
+	r := newReadinessAggregator(hc, observer, nil)
+
+	for i := 0; i < 3; i++ {
+		r.Ready(context.Background())
+	}
+	resp := r.Ready(context.Background())
+
+	entry := resp.Checks["db"]
+	assert.Equal(t, "connecting with password ***", entry.Error)
+	require.Len(t, entry.RecentErrors, 2)
+	for _, recent := range entry.RecentErrors {
+		assert.Equal(t, "connecting with password ***", recent.Error)
+		assert.False(t, recent.Time.IsZero())
+	}
+}
+
+func TestApplication_WithReadinessFailurePayload(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	running := make(chan struct{})
+	app := New().
+		WithContext(ctx).
+		WithSkipConfig(true).
+		WithReadinessFailurePayload(5).
+		// The warmup runs the checks while the app check still fails.
+		WithReadinessWarmup(true, false).
+		WithStartupReport(func(StartupReport) {
+			close(running)
+		})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- app.RunE(func(ctx context.Context, app *Application) ([]goservices.Service, error) {
+			return []goservices.Service{&notReadyResource{err: errors.New("still connecting")}}, nil
+		})
+	}()
+
+	select {
+	case <-running:
+	case <-time.After(time.Second * 5):
+		t.Fatal("the application did not start")
+	}
+
+	resp, err := http.Get("http://localhost:8082/readyz")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var readyz readinessResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&readyz))
+
+	assert.NotEmpty(t, readyz.Checks["not ready"].RecentErrors)
+	require.Contains(t, readyz.Checks, appCheckName)
+	assert.Empty(t, readyz.Checks[appCheckName].RecentErrors, "the app check should not be recorded")
+
+	cancelFunc()
+	require.NoError(t, <-done)
+}
@@ -3,6 +3,8 @@ package application
 import (
 	"context"
 	"os"
+	"sync"
+	"time"
 
 	goservices "github.com/jamillosantos/go-services"
 	svchealthcheck "github.com/jamillosantos/services-healthcheck"
@@ -10,11 +12,29 @@ import (
 
 type healthcheckObserver struct {
 	hc *svchealthcheck.Healthcheck
+
+	historySize int
+	redact      func(message string) string
+	historiesM  sync.Mutex
+	histories   map[string]*errorHistory
+
+	// checkersM guards the checkers as registered, without recording the history, so they can be run on their own.
+	checkersM      sync.Mutex
+	healthCheckers map[string]svchealthcheck.Checker
+	readyCheckers  map[string]svchealthcheck.Checker
 }
 
-func newHealthchekcObserver(hc *svchealthcheck.Healthcheck) *healthcheckObserver {
+// newHealthchekcObserver creates the observer that registers the checks of the services into hc. When historySize is
+// greater than 0, the last historySize errors of each ready check are recorded, redacted by redact (optional).
+func newHealthchekcObserver(hc *svchealthcheck.Healthcheck, historySize int, redact func(message string) string) *healthcheckObserver {
 	return &healthcheckObserver{
-		hc: hc,
+		hc:          hc,
+		historySize: historySize,
+		redact:      redact,
+		histories:   make(map[string]*errorHistory),
+
+		healthCheckers: make(map[string]svchealthcheck.Checker),
+		readyCheckers:  make(map[string]svchealthcheck.Checker),
 	}
 }
 
@@ -23,24 +43,28 @@ func (h *healthcheckObserver) BeforeStart(ctx context.Context, service goservice
 	h.addIfReadyCheck(service)
 }
 
-func (h healthcheckObserver) AfterStart(context.Context, goservices.Service, error) {}
+func (h *healthcheckObserver) AfterStart(context.Context, goservices.Service, error) {}
 
-func (h healthcheckObserver) BeforeStop(context.Context, goservices.Service) {}
+func (h *healthcheckObserver) BeforeStop(context.Context, goservices.Service) {}
 
-func (h healthcheckObserver) AfterStop(context.Context, goservices.Service, error) {}
+func (h *healthcheckObserver) AfterStop(context.Context, goservices.Service, error) {}
 
-func (h healthcheckObserver) BeforeLoad(context.Context, goservices.Configurable) {}
+func (h *healthcheckObserver) BeforeLoad(context.Context, goservices.Configurable) {}
 
-func (h healthcheckObserver) AfterLoad(context.Context, goservices.Configurable, error) {}
+func (h *healthcheckObserver) AfterLoad(context.Context, goservices.Configurable, error) {}
 
-func (h healthcheckObserver) SignalReceived(signal os.Signal) {}
+func (h *healthcheckObserver) SignalReceived(signal os.Signal) {}
 
 func (h *healthcheckObserver) addIfHealthCheck(service goservices.Service) {
 	hc, ok := service.(HealthChecker)
 	if !ok {
 		return
 	}
-	h.hc.AddHealthCheck(service.Name(), svchealthcheck.CheckerFunc(hc.IsHealthy))
+	checker := svchealthcheck.CheckerFunc(hc.IsHealthy)
+	h.checkersM.Lock()
+	h.healthCheckers[service.Name()] = checker
+	h.checkersM.Unlock()
+	h.hc.AddHealthCheck(service.Name(), checker)
 }
 
 func (h *healthcheckObserver) addIfReadyCheck(service goservices.Service) {
@@ -48,5 +72,69 @@ func (h *healthcheckObserver) addIfReadyCheck(service goservices.Service) {
 	if !ok {
 		return
 	}
-	h.hc.AddReadyCheck(service.Name(), svchealthcheck.CheckerFunc(rd.IsReady))
+	h.addReadyCheck(service.Name(), svchealthcheck.CheckerFunc(rd.IsReady))
+}
+
+// addReadyCheck adds the ready check, recording its failures when the recent errors history is enabled.
+func (h *healthcheckObserver) addReadyCheck(name string, checker svchealthcheck.Checker) {
+	h.checkersM.Lock()
+	h.readyCheckers[name] = checker
+	h.checkersM.Unlock()
+
+	if h.historySize <= 0 {
+		h.hc.AddReadyCheck(name, checker)
+		return
+	}
+
+	history := newErrorHistory(h.historySize)
+	h.historiesM.Lock()
+	h.histories[name] = history
+	h.historiesM.Unlock()
+
+	h.hc.AddReadyCheck(name, svchealthcheck.CheckerFunc(func(ctx context.Context) error {
+		err := checker.Check(ctx)
+		if err != nil {
+			history.add(CheckError{
+				Error: h.redactMessage(err.Error()),
+				Time:  time.Now(),
+			})
+		}
+		return err
+	}))
+}
+
+// healthcheckOf returns a svchealthcheck.Healthcheck with only the health and ready checks with the given names. Their
+// failures are not recorded in the recent errors history.
+func (h *healthcheckObserver) healthcheckOf(names []string) *svchealthcheck.Healthcheck {
+	hc := svchealthcheck.NewHealthcheck()
+	h.checkersM.Lock()
+	defer h.checkersM.Unlock()
+	for _, name := range names {
+		if checker, ok := h.healthCheckers[name]; ok {
+			hc.AddHealthCheck(name, checker)
+		}
+		if checker, ok := h.readyCheckers[name]; ok {
+			hc.AddReadyCheck(name, checker)
+		}
+	}
+	return hc
+}
+
+// recentErrors returns the recent errors of the given check. If the history is not enabled, or there is no check with
+// the given name, nil is returned.
+func (h *healthcheckObserver) recentErrors(name string) []CheckError {
+	h.historiesM.Lock()
+	history, ok := h.histories[name]
+	h.historiesM.Unlock()
+	if !ok {
+		return nil
+	}
+	return history.list()
+}
+
+func (h *healthcheckObserver) redactMessage(message string) string {
+	if h.redact == nil || message == "" {
+		return message
+	}
+	return h.redact(message)
 }
@@ -0,0 +1,114 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	svchealthcheck "github.com/jamillosantos/services-healthcheck"
+)
+
+// exitOnUnhealthyCheckInterval is the interval the critical checks of WithExitOnUnhealthy are evaluated when
+// WithBackgroundCheckInterval is not set. It is a variable so the tests can shorten it.
+var exitOnUnhealthyCheckInterval = time.Second * 5
+
+var (
+	ErrUnhealthy = errors.New("critical check unhealthy")
+)
+
+// unhealthyMonitor tracks how long the critical checks have been failing, calling onUnhealthy (only once) when any of
+// them stays failing longer than the threshold.
+type unhealthyMonitor struct {
+	checks      []string
+	after       time.Duration
+	onUnhealthy func(reason string)
+
+	m            sync.Mutex
+	failingSince map[string]time.Time
+	reason       string
+}
+
+func newUnhealthyMonitor(after time.Duration, checks []string, onUnhealthy func(reason string)) *unhealthyMonitor {
+	return &unhealthyMonitor{
+		checks:       checks,
+		after:        after,
+		onUnhealthy:  onUnhealthy,
+		failingSince: make(map[string]time.Time),
+	}
+}
+
+// observe updates the failing checks given the results of the health and ready checks. Checks that are not registered
+// yet are not considered failing.
+func (m *unhealthyMonitor) observe(now time.Time, health *svchealthcheck.CheckResponse, ready *readinessResponse) {
+	m.m.Lock()
+	if m.reason != "" {
+		m.m.Unlock()
+		return
+	}
+	for _, name := range m.checks {
+		errMessage := checkError(name, health, ready)
+		if errMessage == "" {
+			delete(m.failingSince, name)
+			continue
+		}
+		since, ok := m.failingSince[name]
+		if !ok {
+			m.failingSince[name] = now
+			continue
+		}
+		if now.Sub(since) >= m.after {
+			m.reason = fmt.Sprintf("check %s failing for %s: %s", name, now.Sub(since), errMessage)
+			break
+		}
+	}
+	reason := m.reason
+	m.m.Unlock()
+
+	if reason != "" {
+		m.onUnhealthy(reason)
+	}
+}
+
+// unhealthyReason returns the reason the application was flagged as unhealthy. If it was not, an empty string is
+// returned.
+func (m *unhealthyMonitor) unhealthyReason() string {
+	m.m.Lock()
+	defer m.m.Unlock()
+	return m.reason
+}
+
+// checkError returns the error message of the check with the given name, looking into both health and ready results.
+func checkError(name string, health *svchealthcheck.CheckResponse, ready *readinessResponse) string {
+	if entry, ok := health.Checks[name]; ok && entry.Error != "" {
+		return entry.Error
+	}
+	if entry, ok := ready.Checks[name]; ok && entry.Error != "" {
+		return entry.Error
+	}
+	return ""
+}
+
+// unhealthyError returns an ErrUnhealthy if the shutdown was initiated by WithExitOnUnhealthy, nil otherwise.
+func (r *readinessAggregator) unhealthyError() error {
+	if r.monitor == nil {
+		return nil
+	}
+	if reason := r.monitor.unhealthyReason(); reason != "" {
+		return fmt.Errorf("%w: %s", ErrUnhealthy, reason)
+	}
+	return nil
+}
+
+// observeUnhealthy runs only the checks watched by the unhealthy monitor, feeding it without caching the results nor
+// recording the recent errors history.
+func (r *readinessAggregator) observeUnhealthy(ctx context.Context) {
+	hc := r.observer.healthcheckOf(r.monitor.checks)
+	health := hc.Health(ctx)
+	ready := &readinessResponse{Checks: make(map[string]readinessCheckEntry)}
+	for name, entry := range hc.Ready(ctx).Checks {
+		ready.Checks[name] = readinessCheckEntry{CheckResponseEntry: entry}
+	}
+	r.monitor.observe(time.Now(), health, ready)
+}
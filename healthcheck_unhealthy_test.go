@@ -0,0 +1,106 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goservices "github.com/jamillosantos/go-services"
+	svchealthcheck "github.com/jamillosantos/services-healthcheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnhealthyMonitor_observe(t *testing.T) {
+	failing := &readinessResponse{Checks: map[string]readinessCheckEntry{
+		"db": {CheckResponseEntry: svchealthcheck.CheckResponseEntry{Error: "connection refused"}},
+	}}
+	healthy := &readinessResponse{Checks: map[string]readinessCheckEntry{
+		"db": {},
+	}}
+	noHealth := &svchealthcheck.CheckResponse{}
+
+	var reasons []string
+	m := newUnhealthyMonitor(time.Minute, []string{"db"}, func(reason string) {
+		reasons = append(reasons, reason)
+	})
+
+	now := time.Now()
+	m.observe(now, noHealth, failing)
+	m.observe(now.Add(time.Second*30), noHealth, failing)
+	assert.Empty(t, reasons, "should not trigger before the threshold")
+
+	m.observe(now.Add(time.Second*40), noHealth, healthy)
+	m.observe(now.Add(time.Second*50), noHealth, failing)
+	m.observe(now.Add(time.Second*90), noHealth, failing)
+	assert.Empty(t, reasons, "recovering should reset the failing time")
+
+	m.observe(now.Add(time.Second*110), noHealth, failing)
+	m.observe(now.Add(time.Second*120), noHealth, failing)
+	require.Len(t, reasons, 1, "should trigger only once")
+	assert.Contains(t, reasons[0], "connection refused")
+	assert.Equal(t, reasons[0], m.unhealthyReason())
+}
+
+func TestReadinessAggregator_observeUnhealthy(t *testing.T) {
+	hc := svchealthcheck.NewHealthcheck()
+	observer := newHealthchekcObserver(hc, 5, nil)
+	observer.addReadyCheck("db", readyChecker(errors.New("connection refused")))
+	cacheCalls := 0
+	observer.addReadyCheck("cache", svchealthcheck.CheckerFunc(func(context.Context) error {
+		cacheCalls++
+		return nil
+	}))
+	r := newReadinessAggregator(hc, observer, nil)
+	var reasons []string
+	r.monitor = newUnhealthyMonitor(0, []string{"db"}, func(reason string) {
+		reasons = append(reasons, reason)
+	})
+
+	r.observeUnhealthy(context.Background())
+	r.observeUnhealthy(context.Background())
+	assert.Len(t, reasons, 1)
+	assert.Equal(t, 0, cacheCalls, "should run only the watched checks")
+	assert.Empty(t, observer.recentErrors("db"), "should not record the recent errors")
+	assert.Nil(t, r.cachedReady, "should not cache the results")
+	assert.Nil(t, r.cachedHealth, "should not cache the results")
+}
+
+func TestApplication_WithExitOnUnhealthy(t *testing.T) {
+	run := func(t *testing.T, app *Application) {
+		t.Helper()
+		wantErr := errors.New("connection refused")
+		app.
+			WithSkipConfig(true).
+			WithDisableSystemServer(true).
+			WithExitOnUnhealthy(time.Millisecond*50, "not ready")
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- app.RunE(func(ctx context.Context, app *Application) ([]goservices.Service, error) {
+				return []goservices.Service{&notReadyResource{err: wantErr}}, nil
+			})
+		}()
+
+		select {
+		case err := <-errCh:
+			assert.ErrorIs(t, err, ErrUnhealthy)
+			assert.Contains(t, err.Error(), wantErr.Error())
+		case <-time.After(time.Second * 5):
+			t.Fatal("the application did not shut down")
+		}
+	}
+
+	t.Run("should shut down evaluating the checks in the background checks", func(t *testing.T) {
+		run(t, New().WithBackgroundCheckInterval(time.Millisecond*10))
+	})
+
+	t.Run("should shut down evaluating the checks on their own", func(t *testing.T) {
+		interval := exitOnUnhealthyCheckInterval
+		exitOnUnhealthyCheckInterval = time.Millisecond * 10
+		t.Cleanup(func() { exitOnUnhealthyCheckInterval = interval })
+
+		run(t, New())
+	})
+}
@@ -0,0 +1,61 @@
+package application
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// fieldDropperCore is a zapcore.Core that removes the fields with the given keys before writing the entries.
+//
+// It adds itself to the checked entries instead of delegating to the wrapped core, so it must wrap the core that writes
+// the entries directly. Any core that decides which entries are written, such as the sampler, must wrap it instead.
+type fieldDropperCore struct {
+	zapcore.Core
+	keys map[string]struct{}
+}
+
+func newFieldDropperCore(core zapcore.Core, keys []string) *fieldDropperCore {
+	m := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		m[key] = struct{}{}
+	}
+	return &fieldDropperCore{
+		Core: core,
+		keys: m,
+	}
+}
+
+func (c *fieldDropperCore) With(fields []zapcore.Field) zapcore.Core {
+	return &fieldDropperCore{
+		Core: c.Core.With(c.filter(fields)),
+		keys: c.keys,
+	}
+}
+
+func (c *fieldDropperCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *fieldDropperCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.filter(fields))
+}
+
+// filter returns the fields without the dropped keys. The given slice is returned as is when no field is dropped.
+func (c *fieldDropperCore) filter(fields []zapcore.Field) []zapcore.Field {
+	for i, field := range fields {
+		if _, ok := c.keys[field.Key]; !ok {
+			continue
+		}
+		r := make([]zapcore.Field, i, len(fields)-1)
+		copy(r, fields[:i])
+		for _, f := range fields[i+1:] {
+			if _, ok := c.keys[f.Key]; !ok {
+				r = append(r, f)
+			}
+		}
+		return r
+	}
+	return fields
+}
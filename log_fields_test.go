@@ -0,0 +1,69 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestApplication_WithDroppedLogFields(t *testing.T) {
+	newLogger := func(environment string) (*zap.Logger, *observer.ObservedLogs) {
+		app := New().
+			WithEnvironment(environment).
+			WithDroppedLogFields("production", "body", "headers")
+		core, logs := observer.New(zap.DebugLevel)
+		return zap.New(app.wrapLoggerCore(core)), logs
+	}
+
+	t.Run("should drop the fields in the configured environment", func(t *testing.T) {
+		logger, logs := newLogger("production")
+		logger.With(zap.String("headers", "h")).Info("request", zap.String("body", "b"), zap.String("path", "/"))
+
+		require.Equal(t, 1, logs.Len())
+		fields := logs.All()[0].ContextMap()
+		assert.NotContains(t, fields, "body")
+		assert.NotContains(t, fields, "headers")
+		assert.Equal(t, "/", fields["path"])
+	})
+
+	t.Run("should keep the fields in other environments", func(t *testing.T) {
+		logger, logs := newLogger("dev")
+		logger.With(zap.String("headers", "h")).Info("request", zap.String("body", "b"), zap.String("path", "/"))
+
+		require.Equal(t, 1, logs.Len())
+		fields := logs.All()[0].ContextMap()
+		assert.Equal(t, "b", fields["body"])
+		assert.Equal(t, "h", fields["headers"])
+		assert.Equal(t, "/", fields["path"])
+	})
+}
+
+func TestApplication_buildLogger_droppedLogFieldsSampling(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output.log")
+	app := New().
+		WithEnvironment("production").
+		WithDroppedLogFields("production", "body").
+		WithZapConfigModifier(func(cfg *zap.Config) {
+			cfg.OutputPaths = []string{output}
+		})
+	logger, err := app.buildLogger()
+	require.NoError(t, err)
+
+	for i := 0; i < 1000; i++ {
+		logger.Info("request", zap.String("body", "b"))
+	}
+	require.NoError(t, logger.Sync())
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	// The production sampling logs the first 100 entries per second, and then every 100th.
+	assert.Less(t, len(lines), 200)
+	assert.NotContains(t, lines[0], `"body"`)
+}
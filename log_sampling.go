@@ -0,0 +1,124 @@
+package application
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// logSamplerCountersPerLevel is the number of counters per level. Messages are spread across them by their hash, the
+// same way the zapcore sampler does.
+const logSamplerCountersPerLevel = 4096
+
+// logSamplingParams are the parameters of the logSampler. In each tick, the first `initial` entries with the same
+// level and message are logged, and then every `thereafter`th entry. A thereafter of 0 drops all entries after the
+// initial ones.
+type logSamplingParams struct {
+	tick       time.Duration
+	initial    uint64
+	thereafter uint64
+}
+
+// logSampler mirrors the zapcore sampler, but its parameters can be swapped atomically so the sampling can be changed
+// in place when the configuration is reloaded. When there are no parameters, all entries are logged.
+type logSampler struct {
+	params   atomic.Pointer[logSamplingParams]
+	hook     func(zapcore.Entry, zapcore.SamplingDecision)
+	counters [zapcore.FatalLevel - zapcore.DebugLevel + 1][logSamplerCountersPerLevel]logSamplerCounter
+}
+
+func newLogSampler(sampling *logSamplingParams, hook func(zapcore.Entry, zapcore.SamplingDecision)) *logSampler {
+	s := &logSampler{
+		hook: hook,
+	}
+	s.params.Store(sampling)
+	return s
+}
+
+// set replaces the sampling parameters. A nil params disables the sampling.
+func (s *logSampler) set(params *logSamplingParams) {
+	s.params.Store(params)
+}
+
+// allow returns true if the entry should be logged.
+func (s *logSampler) allow(ent zapcore.Entry) bool {
+	params := s.params.Load()
+	if params == nil || ent.Level < zapcore.DebugLevel || ent.Level > zapcore.FatalLevel {
+		return true
+	}
+	counter := &s.counters[ent.Level-zapcore.DebugLevel][fnv32a(ent.Message)%logSamplerCountersPerLevel]
+	n := counter.incCheckReset(ent.Time, params.tick)
+	if n > params.initial && (params.thereafter == 0 || (n-params.initial)%params.thereafter != 0) {
+		if s.hook != nil {
+			s.hook(ent, zapcore.LogDropped)
+		}
+		return false
+	}
+	if s.hook != nil {
+		s.hook(ent, zapcore.LogSampled)
+	}
+	return true
+}
+
+type logSamplerCounter struct {
+	resetAt atomic.Int64
+	counter atomic.Uint64
+}
+
+// incCheckReset increments the counter, resetting it when the tick has elapsed.
+func (c *logSamplerCounter) incCheckReset(t time.Time, tick time.Duration) uint64 {
+	tn := t.UnixNano()
+	resetAt := c.resetAt.Load()
+	if resetAt > tn {
+		return c.counter.Add(1)
+	}
+
+	c.counter.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, tn+tick.Nanoseconds()) {
+		// Another goroutine reset the counter.
+		return c.counter.Add(1)
+	}
+	return 1
+}
+
+// fnv32a is the 32-bit FNV-1a hash of the string, without allocating.
+func fnv32a(s string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	hash := uint32(offset32)
+	for i := 0; i < len(s); i++ {
+		hash ^= uint32(s[i])
+		hash *= prime32
+	}
+	return hash
+}
+
+// samplerCore is a zapcore.Core that samples the entries using a logSampler shared with all cores derived from it.
+type samplerCore struct {
+	zapcore.Core
+	sampler *logSampler
+}
+
+func newSamplerCore(core zapcore.Core, sampler *logSampler) *samplerCore {
+	return &samplerCore{
+		Core:    core,
+		sampler: sampler,
+	}
+}
+
+func (c *samplerCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplerCore{
+		Core:    c.Core.With(fields),
+		sampler: c.sampler,
+	}
+}
+
+func (c *samplerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) || !c.sampler.allow(ent) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
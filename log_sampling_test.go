@@ -0,0 +1,44 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSamplerCore(t *testing.T) {
+	t.Run("should sample the entries with the same message", func(t *testing.T) {
+		core, logs := observer.New(zap.DebugLevel)
+		sampler := newLogSampler(&logSamplingParams{tick: time.Minute, initial: 2, thereafter: 3}, nil)
+		logger := zap.New(newSamplerCore(core, sampler)).With(zap.String("k", "v"))
+
+		for i := 0; i < 10; i++ {
+			logger.Info("sampled")
+		}
+		logger.Info("other")
+
+		// 2 initial entries, then every 3rd (5th and 8th), plus the other message.
+		assert.Equal(t, 4, logs.FilterMessage("sampled").Len())
+		assert.Equal(t, 1, logs.FilterMessage("other").Len())
+	})
+
+	t.Run("should change the sampling in place", func(t *testing.T) {
+		core, logs := observer.New(zap.DebugLevel)
+		sampler := newLogSampler(nil, nil)
+		logger := zap.New(newSamplerCore(core, sampler))
+
+		for i := 0; i < 10; i++ {
+			logger.Info("message")
+		}
+		assert.Len(t, logs.TakeAll(), 10, "sampling is disabled")
+
+		sampler.set(&logSamplingParams{tick: time.Minute, initial: 1})
+		for i := 0; i < 10; i++ {
+			logger.Info("message")
+		}
+		assert.Equal(t, 1, logs.Len())
+	})
+}
@@ -0,0 +1,55 @@
+package application
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// buildLogger creates the logger based on the environment of the application. The level and the sampler of the logger
+// are kept so they can be changed in place when the configuration is reloaded.
+func (app *Application) buildLogger() (*zap.Logger, error) {
+	var zapcfg zap.Config
+	switch app.environment {
+	case "dev":
+		zapcfg = zap.NewDevelopmentConfig()
+	default:
+		zapcfg = zap.NewProductionConfig()
+	}
+	if app.zapConfigModifier != nil {
+		app.zapConfigModifier(&zapcfg)
+	}
+	zapcfg.DisableStacktrace = true
+	zapcfg.EncoderConfig.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	app.loggerLevel = zapcfg.Level
+
+	// The sampling of zap cannot be changed after the logger is built, so it is replaced by a reloadable sampler.
+	var sampling *logSamplingParams
+	var samplingHook func(zapcore.Entry, zapcore.SamplingDecision)
+	if zapcfg.Sampling != nil {
+		sampling = &logSamplingParams{
+			tick:       time.Second,
+			initial:    uint64(zapcfg.Sampling.Initial),
+			thereafter: uint64(zapcfg.Sampling.Thereafter),
+		}
+		samplingHook = zapcfg.Sampling.Hook
+		zapcfg.Sampling = nil
+	}
+	app.loggerSampler = newLogSampler(sampling, samplingHook)
+
+	opts := append([]zap.Option{zap.WrapCore(app.wrapLoggerCore)}, app.loggerZapOptions...)
+	return zapcfg.Build(opts...)
+}
+
+// wrapLoggerCore wraps the core that writes the entries with the field dropper of the environment and then with the
+// sampler. The field dropper must stay below the sampler, since it writes the entries by itself.
+func (app *Application) wrapLoggerCore(core zapcore.Core) zapcore.Core {
+	if keys := app.droppedLogFields[app.environment]; len(keys) > 0 {
+		core = newFieldDropperCore(core, keys)
+	}
+	if app.loggerSampler != nil {
+		core = newSamplerCore(core, app.loggerSampler)
+	}
+	return core
+}
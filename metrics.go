@@ -0,0 +1,38 @@
+package application
+
+import (
+	"strings"
+)
+
+// MetricsNamespace returns the namespace applied to the metrics of the application. Services should use it to prefix
+// their metrics (eg: `myapp_service_up`), avoiding clashes with other libraries sharing the same registry.
+//
+// If WithMetricsNamespace was not used, it defaults to the sanitized application name. Since the name can be extracted
+// from the build info, the default is only available once the application runs (eg: in the ServiceSetup).
+func (app *Application) MetricsNamespace() string {
+	if app.metricsNamespace != "" {
+		return app.metricsNamespace
+	}
+	return sanitizeMetricsNamespace(app.name)
+}
+
+// sanitizeMetricsNamespace converts the given name into a valid Prometheus namespace, lowercasing it and replacing any
+// character that is not a letter, a digit or an underscore with an underscore.
+func sanitizeMetricsNamespace(name string) string {
+	var sb strings.Builder
+	sb.Grow(len(name))
+	for i, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r == '_':
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,34 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplication_MetricsNamespace(t *testing.T) {
+	t.Run("should default to the sanitized app name", func(t *testing.T) {
+		app := New().WithName("My-App.api")
+		assert.Equal(t, "my_app_api", app.MetricsNamespace())
+	})
+
+	t.Run("should use the given namespace", func(t *testing.T) {
+		app := New().WithName("my-app").WithMetricsNamespace("custom")
+		assert.Equal(t, "custom", app.MetricsNamespace())
+	})
+}
+
+func Test_sanitizeMetricsNamespace(t *testing.T) {
+	tests := map[string]string{
+		"myapp":       "myapp",
+		"my-app":      "my_app",
+		"MyApp":       "myapp",
+		"1app":        "_1app",
+		"app v2":      "app_v2",
+		"app/service": "app_service",
+		"":            "",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, sanitizeMetricsNamespace(name), name)
+	}
+}
@@ -0,0 +1,252 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jamillosantos/logctx"
+	svchealthcheck "github.com/jamillosantos/services-healthcheck"
+	"go.uber.org/zap"
+)
+
+// ReadinessMode defines how the checks of a readiness group are aggregated.
+type ReadinessMode string
+
+const (
+	// AllReady requires all services of the group to be ready.
+	AllReady ReadinessMode = "all"
+	// AnyReady requires at least one service of the group to be ready. Useful for active/standby topologies.
+	AnyReady ReadinessMode = "any"
+)
+
+// readinessWarmupRetryInterval is the interval between the warmups when WithReadinessWarmup blocks on failure.
+const readinessWarmupRetryInterval = time.Second
+
+// readinessGroupPrefix prefixes the name of the readiness group entries in the ready response, so they never collide
+// with the checks.
+const readinessGroupPrefix = "group:"
+
+var (
+	ErrReadinessGroupNotReady = errors.New("readiness group is not ready")
+	ErrInvalidReadinessMode   = errors.New("invalid readiness mode")
+	ErrEmptyReadinessGroup    = errors.New("readiness group without services")
+)
+
+// readinessResponse is the svchealthcheck.CheckResponse for the ready endpoint, including the recent errors of each
+// check.
+type readinessResponse struct {
+	StatusCode int                            `json:"-"`
+	Status     string                         `json:"status"`
+	Checks     map[string]readinessCheckEntry `json:"checks"`
+}
+
+type readinessCheckEntry struct {
+	svchealthcheck.CheckResponseEntry
+	RecentErrors []CheckError `json:"recent_errors,omitempty"`
+}
+
+type readinessGroup struct {
+	name     string
+	mode     ReadinessMode
+	services []string
+}
+
+// readinessAggregator wraps the svchealthcheck.Healthcheck applying the readiness groups to the ready response and
+// adding the recent errors recorded by the healthcheckObserver.
+//
+// When the results are cached (check refresh), the endpoints are served from the cache instead of running the checks.
+type readinessAggregator struct {
+	hc       *svchealthcheck.Healthcheck
+	groups   []readinessGroup
+	observer *healthcheckObserver
+
+	// refreshM runs one refresh at a time, so an older run never overwrites the cache of a newer one.
+	refreshM     sync.Mutex
+	cacheM       sync.RWMutex
+	cachedHealth *svchealthcheck.CheckResponse
+	cachedReady  *readinessResponse
+
+	monitor *unhealthyMonitor
+}
+
+func newReadinessAggregator(hc *svchealthcheck.Healthcheck, observer *healthcheckObserver, groups []readinessGroup) *readinessAggregator {
+	return &readinessAggregator{
+		hc:       hc,
+		groups:   groups,
+		observer: observer,
+	}
+}
+
+// Health returns the cached health response, running the checks if there is none.
+func (r *readinessAggregator) Health(ctx context.Context) *svchealthcheck.CheckResponse {
+	r.cacheM.RLock()
+	resp := r.cachedHealth
+	r.cacheM.RUnlock()
+	if resp != nil {
+		return resp
+	}
+	return r.hc.Health(ctx)
+}
+
+// Ready returns the cached ready response, running the checks if there is none.
+func (r *readinessAggregator) Ready(ctx context.Context) *readinessResponse {
+	r.cacheM.RLock()
+	resp := r.cachedReady
+	r.cacheM.RUnlock()
+	if resp != nil {
+		return resp
+	}
+	return r.checkReady(ctx)
+}
+
+// refresh runs all health and ready checks, caching the results to be served by the endpoints. Concurrent calls run
+// one after the other.
+func (r *readinessAggregator) refresh(ctx context.Context) {
+	r.refreshM.Lock()
+	defer r.refreshM.Unlock()
+
+	health := r.hc.Health(ctx)
+	ready := r.checkReady(ctx)
+
+	r.cacheM.Lock()
+	r.cachedHealth, r.cachedReady = health, ready
+	r.cacheM.Unlock()
+
+	if r.monitor != nil {
+		r.monitor.observe(time.Now(), health, ready)
+	}
+}
+
+// checkReady runs the ready checks and aggregates their results.
+func (r *readinessAggregator) checkReady(ctx context.Context) *readinessResponse {
+	resp := r.hc.Ready(ctx)
+	if len(r.groups) > 0 {
+		resp = r.aggregate(resp)
+	}
+
+	checks := make(map[string]readinessCheckEntry, len(resp.Checks))
+	for name, entry := range resp.Checks {
+		entry.Error = r.observer.redactMessage(entry.Error)
+		checks[name] = readinessCheckEntry{
+			CheckResponseEntry: entry,
+			RecentErrors:       r.observer.recentErrors(name),
+		}
+	}
+	return &readinessResponse{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Checks:     checks,
+	}
+}
+
+// warmup evaluates all ready checks once, warming caches and connection pools before the application is flagged as
+// ready. Failures are logged and it returns false if any check, other than the grouped ones, failed. The app check is
+// skipped, since it is expected to fail until the application is running.
+func (r *readinessAggregator) warmup(ctx context.Context) bool {
+	logger := logctx.From(ctx)
+	resp := r.checkReady(ctx)
+	ready := true
+	for name, entry := range resp.Checks {
+		if name == appCheckName || entry.Error == "" {
+			continue
+		}
+		logger.Warn("readiness warmup check failed", zap.String("check", name), zap.String("error", entry.Error))
+		if !r.isGrouped(name) {
+			ready = false
+		}
+	}
+	return ready
+}
+
+// warmupUntilReady runs the warmup every retryInterval until it succeeds. If the ctx is done before that, it returns
+// the ctx error.
+func (r *readinessAggregator) warmupUntilReady(ctx context.Context, retryInterval time.Duration) error {
+	for !r.warmup(ctx) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryInterval):
+		}
+	}
+	return nil
+}
+
+// isGrouped returns true if the check belongs to a readiness group, so its failure alone does not fail the readiness.
+func (r *readinessAggregator) isGrouped(name string) bool {
+	for _, group := range r.groups {
+		for _, service := range group.services {
+			if service == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// aggregate recomputes the status of the response honoring the mode of each readiness group. Checks that do not
+// belong to any group must be ready, as usual. When a group is not satisfied, an entry named "group:<name>" is added to
+// the response describing the failure.
+func (r *readinessAggregator) aggregate(resp *svchealthcheck.CheckResponse) *svchealthcheck.CheckResponse {
+	statusCode := http.StatusOK
+
+	grouped := make(map[string]struct{})
+	for _, group := range r.groups {
+		for _, service := range group.services {
+			grouped[service] = struct{}{}
+		}
+		if !group.isReady(resp.Checks) {
+			statusCode = readinessStatusCode(statusCode, ErrReadinessGroupNotReady.Error())
+			resp.Checks[readinessGroupPrefix+group.name] = svchealthcheck.CheckResponseEntry{
+				Error: ErrReadinessGroupNotReady.Error() + ": " + string(group.mode) + " of " + strings.Join(group.services, ", "),
+			}
+		}
+	}
+
+	for name, entry := range resp.Checks {
+		if _, ok := grouped[name]; ok || entry.Error == "" {
+			continue
+		}
+		statusCode = readinessStatusCode(statusCode, entry.Error)
+	}
+
+	resp.StatusCode = statusCode
+	resp.Status = http.StatusText(statusCode)
+	return resp
+}
+
+// isReady checks if the group is ready given the checks results. Services that are not registered are considered not
+// ready.
+func (g readinessGroup) isReady(checks map[string]svchealthcheck.CheckResponseEntry) bool {
+	readyCount := 0
+	for _, service := range g.services {
+		entry, ok := checks[service]
+		if ok && entry.Error == "" {
+			readyCount++
+		}
+	}
+	if g.mode == AnyReady {
+		return readyCount > 0
+	}
+	return readyCount == len(g.services)
+}
+
+// isValid returns true if the mode is one of the known modes.
+func (mode ReadinessMode) isValid() bool {
+	return mode == AllReady || mode == AnyReady
+}
+
+// readinessStatusCode mirrors the status code escalation of the svchealthcheck package: panics result in an internal
+// server error, any other failure results in service unavailable.
+func readinessStatusCode(code int, errMessage string) int {
+	if strings.HasPrefix(errMessage, svchealthcheck.ErrCheckerPanic.Error()) {
+		return http.StatusInternalServerError
+	}
+	if code == http.StatusOK {
+		return http.StatusServiceUnavailable
+	}
+	return code
+}
@@ -0,0 +1,240 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	goservices "github.com/jamillosantos/go-services"
+	"github.com/jamillosantos/logctx"
+	svchealthcheck "github.com/jamillosantos/services-healthcheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func readyChecker(err error) svchealthcheck.Checker {
+	return svchealthcheck.CheckerFunc(func(context.Context) error {
+		return err
+	})
+}
+
+func TestReadinessAggregator_Ready(t *testing.T) {
+	errNotReady := errors.New("not ready")
+
+	t.Run("should be ready when any service of an AnyReady group is ready", func(t *testing.T) {
+		hc := svchealthcheck.NewHealthcheck(
+			svchealthcheck.WithReadyCheck("primary", readyChecker(errNotReady)),
+			svchealthcheck.WithReadyCheck("standby", readyChecker(nil)),
+		)
+		r := newReadinessAggregator(hc, newHealthchekcObserver(hc, 0, nil), []readinessGroup{
+			{name: "db", mode: AnyReady, services: []string{"primary", "standby"}},
+		})
+
+		resp := r.Ready(context.Background())
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, errNotReady.Error(), resp.Checks["primary"].Error)
+		assert.NotContains(t, resp.Checks, "group:db")
+	})
+
+	t.Run("should not be ready when no service of an AnyReady group is ready", func(t *testing.T) {
+		hc := svchealthcheck.NewHealthcheck(
+			svchealthcheck.WithReadyCheck("primary", readyChecker(errNotReady)),
+			svchealthcheck.WithReadyCheck("standby", readyChecker(errNotReady)),
+		)
+		r := newReadinessAggregator(hc, newHealthchekcObserver(hc, 0, nil), []readinessGroup{
+			{name: "db", mode: AnyReady, services: []string{"primary", "standby"}},
+		})
+
+		resp := r.Ready(context.Background())
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Contains(t, resp.Checks["group:db"].Error, ErrReadinessGroupNotReady.Error())
+	})
+
+	t.Run("should not overwrite a check with the same name of a group", func(t *testing.T) {
+		hc := svchealthcheck.NewHealthcheck(
+			svchealthcheck.WithReadyCheck("db", readyChecker(errNotReady)),
+		)
+		r := newReadinessAggregator(hc, newHealthchekcObserver(hc, 0, nil), []readinessGroup{
+			{name: "db", mode: AnyReady, services: []string{"db"}},
+		})
+
+		resp := r.Ready(context.Background())
+		assert.Equal(t, errNotReady.Error(), resp.Checks["db"].Error)
+		assert.Contains(t, resp.Checks["group:db"].Error, ErrReadinessGroupNotReady.Error())
+	})
+
+	t.Run("should not be ready when a service of an AllReady group is not ready", func(t *testing.T) {
+		hc := svchealthcheck.NewHealthcheck(
+			svchealthcheck.WithReadyCheck("a", readyChecker(nil)),
+			svchealthcheck.WithReadyCheck("b", readyChecker(errNotReady)),
+		)
+		r := newReadinessAggregator(hc, newHealthchekcObserver(hc, 0, nil), []readinessGroup{
+			{name: "group", mode: AllReady, services: []string{"a", "b"}},
+		})
+
+		resp := r.Ready(context.Background())
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+
+	t.Run("should consider unregistered services not ready", func(t *testing.T) {
+		hc := svchealthcheck.NewHealthcheck()
+		r := newReadinessAggregator(hc, newHealthchekcObserver(hc, 0, nil), []readinessGroup{
+			{name: "db", mode: AnyReady, services: []string{"primary", "standby"}},
+		})
+
+		resp := r.Ready(context.Background())
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+
+	t.Run("should not be ready when a service outside the groups is not ready", func(t *testing.T) {
+		hc := svchealthcheck.NewHealthcheck(
+			svchealthcheck.WithReadyCheck("app", readyChecker(ErrAppNotRunningYet)),
+			svchealthcheck.WithReadyCheck("primary", readyChecker(nil)),
+		)
+		r := newReadinessAggregator(hc, newHealthchekcObserver(hc, 0, nil), []readinessGroup{
+			{name: "db", mode: AnyReady, services: []string{"primary", "standby"}},
+		})
+
+		resp := r.Ready(context.Background())
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+}
+
+func TestReadinessAggregator_warmup(t *testing.T) {
+	t.Run("should run the checks once and log the failures", func(t *testing.T) {
+		called := 0
+		hc := svchealthcheck.NewHealthcheck(
+			svchealthcheck.WithReadyCheck(appCheckName, readyChecker(ErrAppNotRunningYet)),
+			svchealthcheck.WithReadyCheck("db", readyChecker(errors.New("connection refused"))),
+			svchealthcheck.WithReadyCheck("cache", svchealthcheck.CheckerFunc(func(context.Context) error {
+				called++
+				return nil
+			})),
+		)
+		r := newReadinessAggregator(hc, newHealthchekcObserver(hc, 0, nil), nil)
+
+		core, logs := observer.New(zap.DebugLevel)
+		assert.False(t, r.warmup(logctx.WithLogger(context.Background(), zap.New(core))))
+
+		assert.Equal(t, 1, called)
+		require.Equal(t, 1, logs.Len(), "only the db failure should be logged")
+		assert.Equal(t, "db", logs.All()[0].ContextMap()["check"])
+	})
+
+	t.Run("should succeed when only grouped checks fail within a ready group", func(t *testing.T) {
+		hc := svchealthcheck.NewHealthcheck(
+			svchealthcheck.WithReadyCheck(appCheckName, readyChecker(ErrAppNotRunningYet)),
+			svchealthcheck.WithReadyCheck("primary", readyChecker(errors.New("connection refused"))),
+			svchealthcheck.WithReadyCheck("standby", readyChecker(nil)),
+		)
+		r := newReadinessAggregator(hc, newHealthchekcObserver(hc, 0, nil), []readinessGroup{
+			{name: "db", mode: AnyReady, services: []string{"primary", "standby"}},
+		})
+
+		assert.True(t, r.warmup(context.Background()))
+	})
+}
+
+func TestReadinessAggregator_warmupUntilReady(t *testing.T) {
+	t.Run("should retry until all checks pass", func(t *testing.T) {
+		called := 0
+		hc := svchealthcheck.NewHealthcheck(
+			svchealthcheck.WithReadyCheck("db", svchealthcheck.CheckerFunc(func(context.Context) error {
+				called++
+				if called < 3 {
+					return errors.New("connection refused")
+				}
+				return nil
+			})),
+		)
+		r := newReadinessAggregator(hc, newHealthchekcObserver(hc, 0, nil), nil)
+
+		require.NoError(t, r.warmupUntilReady(context.Background(), time.Millisecond))
+		assert.Equal(t, 3, called)
+	})
+
+	t.Run("should stop when the context is done", func(t *testing.T) {
+		hc := svchealthcheck.NewHealthcheck(
+			svchealthcheck.WithReadyCheck("db", readyChecker(errors.New("connection refused"))),
+		)
+		r := newReadinessAggregator(hc, newHealthchekcObserver(hc, 0, nil), nil)
+
+		ctx, cancelFunc := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer cancelFunc()
+		assert.ErrorIs(t, r.warmupUntilReady(ctx, time.Millisecond*10), context.DeadlineExceeded)
+	})
+}
+
+func TestApplication_WithReadinessGroup(t *testing.T) {
+	t.Run("should add the group", func(t *testing.T) {
+		app := New().WithReadinessGroup("db", AnyReady, "primary", "standby")
+		require.Len(t, app.readinessGroups, 1)
+		assert.Equal(t, readinessGroup{name: "db", mode: AnyReady, services: []string{"primary", "standby"}}, app.readinessGroups[0])
+	})
+
+	t.Run("should panic given an invalid mode", func(t *testing.T) {
+		assert.PanicsWithError(t, `invalid readiness mode: "some"`, func() {
+			New().WithReadinessGroup("db", ReadinessMode("some"), "primary")
+		})
+	})
+
+	t.Run("should panic given no services", func(t *testing.T) {
+		assert.PanicsWithError(t, `readiness group without services: "db"`, func() {
+			New().WithReadinessGroup("db", AnyReady)
+		})
+	})
+}
+
+func TestApplication_WithReadinessWarmup(t *testing.T) {
+	// run starts the application with a service that is never ready, cancelling it after the given delay.
+	run := func(t *testing.T, blockOnFailure bool, cancelAfter time.Duration) (*Application, StartupReport) {
+		t.Helper()
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		defer cancelFunc()
+
+		reports := make(chan StartupReport, 1)
+		app := New().
+			WithContext(ctx).
+			WithSkipConfig(true).
+			WithDisableSystemServer(true).
+			WithReadinessWarmup(true, blockOnFailure).
+			WithStartupReport(func(report StartupReport) {
+				reports <- report
+			})
+
+		done := make(chan error, 1)
+		go func() {
+			done <- app.RunE(func(ctx context.Context, app *Application) ([]goservices.Service, error) {
+				return []goservices.Service{&notReadyResource{err: errors.New("still connecting")}}, nil
+			})
+		}()
+
+		var report StartupReport
+		select {
+		case report = <-reports:
+		case <-time.After(cancelAfter):
+			cancelFunc()
+			report = <-reports
+		}
+		cancelFunc()
+		require.NoError(t, <-done)
+		return app, report
+	}
+
+	t.Run("should flag the application as ready when the warmup fails", func(t *testing.T) {
+		app, report := run(t, false, time.Second*5)
+		assert.Equal(t, StartupRunning, report.State)
+		assert.Equal(t, stateRunning, app.state)
+	})
+
+	t.Run("should fail the startup when shut down while the warmup fails", func(t *testing.T) {
+		app, report := run(t, true, time.Millisecond*200)
+		assert.Equal(t, StartupFailed, report.State)
+		assert.ErrorIs(t, report.Err, context.Canceled)
+		assert.NotEqual(t, stateRunning, app.state, "the application should never be flagged as running")
+	})
+}
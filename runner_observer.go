@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	goservices "github.com/jamillosantos/go-services"
+)
+
+// runnerObserver is a goservices.Observer that forwards the events to the observers of the current run. The observers
+// of a goservices.Runner cannot be removed, so a runner given to WithRunner gets a single runnerObserver, whose
+// observers are replaced on each run instead of piling up.
+type runnerObserver struct {
+	observersM sync.RWMutex
+	observers  []goservices.Observer
+}
+
+// set replaces the observers that receive the events.
+func (r *runnerObserver) set(observers ...goservices.Observer) {
+	r.observersM.Lock()
+	r.observers = observers
+	r.observersM.Unlock()
+}
+
+func (r *runnerObserver) each(f func(goservices.Observer)) {
+	r.observersM.RLock()
+	observers := r.observers
+	r.observersM.RUnlock()
+	for _, o := range observers {
+		f(o)
+	}
+}
+
+func (r *runnerObserver) BeforeStart(ctx context.Context, service goservices.Service) {
+	r.each(func(o goservices.Observer) { o.BeforeStart(ctx, service) })
+}
+
+func (r *runnerObserver) AfterStart(ctx context.Context, service goservices.Service, err error) {
+	r.each(func(o goservices.Observer) { o.AfterStart(ctx, service, err) })
+}
+
+func (r *runnerObserver) BeforeStop(ctx context.Context, service goservices.Service) {
+	r.each(func(o goservices.Observer) { o.BeforeStop(ctx, service) })
+}
+
+func (r *runnerObserver) AfterStop(ctx context.Context, service goservices.Service, err error) {
+	r.each(func(o goservices.Observer) { o.AfterStop(ctx, service, err) })
+}
+
+func (r *runnerObserver) BeforeLoad(ctx context.Context, configurable goservices.Configurable) {
+	r.each(func(o goservices.Observer) { o.BeforeLoad(ctx, configurable) })
+}
+
+func (r *runnerObserver) AfterLoad(ctx context.Context, configurable goservices.Configurable, err error) {
+	r.each(func(o goservices.Observer) { o.AfterLoad(ctx, configurable, err) })
+}
+
+func (r *runnerObserver) SignalReceived(signal os.Signal) {
+	r.each(func(o goservices.Observer) { o.SignalReceived(signal) })
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package application
+
+import (
+	"go.uber.org/zap"
+)
+
+// handleSIGPIPE does nothing, SIGPIPE is not raised on this platform.
+func handleSIGPIPE(*zap.Logger) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,43 @@
+//go:build unix
+
+package application
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// handleSIGPIPE prevents the process from being killed when writing to a closed pipe or socket (eg: logs piped to
+// `head` or to a log shipper that died). Once SIGPIPE is notified, the Go runtime does not terminate the process and
+// the writes fail with EPIPE instead. A single warning is logged when the signal is first received.
+//
+// The returned function stops handling the signal. It must be called only after the last write of the application,
+// including the logs of the graceful shutdown.
+func handleSIGPIPE(logger *zap.Logger) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGPIPE)
+
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ch:
+				once.Do(func() {
+					logger.Warn("SIGPIPE received: writing to a closed pipe or socket, ignoring it")
+				})
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
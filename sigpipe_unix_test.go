@@ -0,0 +1,29 @@
+//go:build unix
+
+package application
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func Test_handleSIGPIPE(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	stop := handleSIGPIPE(zap.New(core))
+	defer stop()
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGPIPE))
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGPIPE))
+
+	require.Eventually(t, func() bool {
+		return logs.Len() > 0
+	}, time.Second, time.Millisecond*10)
+	time.Sleep(time.Millisecond * 100)
+	assert.Equal(t, 1, logs.Len(), "the warning should be logged only once")
+}
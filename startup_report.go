@@ -0,0 +1,114 @@
+package application
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	goservices "github.com/jamillosantos/go-services"
+)
+
+// StartupState is the final state of the application startup.
+type StartupState string
+
+const (
+	// StartupRunning means all services started and the application is running.
+	StartupRunning StartupState = "running"
+	// StartupFailed means the application failed to start.
+	StartupFailed StartupState = "failed"
+	// StartupNoServices means the setup returned no services to run.
+	StartupNoServices StartupState = "no_services"
+)
+
+// StartupReport describes how the application started: which services started, which failed, their timings and the
+// final state.
+type StartupReport struct {
+	State StartupState
+	// Err is the error that made the startup fail, if any.
+	Err      error
+	Duration time.Duration
+	// Services lists the services, in the order they were started.
+	Services []ServiceStartupReport
+}
+
+// ServiceStartupReport describes the startup of a single service.
+type ServiceStartupReport struct {
+	Name     string
+	Started  bool
+	Err      error
+	Duration time.Duration
+}
+
+// startupReporter is a goservices.Observer that accumulates the StartupReport while the services start, handing it to
+// the handler when the startup finishes.
+type startupReporter struct {
+	handler func(StartupReport)
+
+	m         sync.Mutex
+	startedAt time.Time
+	starting  map[string]time.Time
+	services  []ServiceStartupReport
+	done      bool
+}
+
+func newStartupReporter(handler func(StartupReport)) *startupReporter {
+	return &startupReporter{
+		handler:   handler,
+		startedAt: time.Now(),
+		starting:  make(map[string]time.Time),
+	}
+}
+
+func (r *startupReporter) BeforeStart(_ context.Context, service goservices.Service) {
+	r.m.Lock()
+	r.starting[service.Name()] = time.Now()
+	r.m.Unlock()
+}
+
+func (r *startupReporter) AfterStart(_ context.Context, service goservices.Service, err error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.done {
+		return
+	}
+	r.services = append(r.services, ServiceStartupReport{
+		Name:     service.Name(),
+		Started:  err == nil,
+		Err:      err,
+		Duration: time.Since(r.starting[service.Name()]),
+	})
+	delete(r.starting, service.Name())
+}
+
+func (r *startupReporter) BeforeStop(context.Context, goservices.Service) {}
+
+func (r *startupReporter) AfterStop(context.Context, goservices.Service, error) {}
+
+func (r *startupReporter) BeforeLoad(context.Context, goservices.Configurable) {}
+
+func (r *startupReporter) AfterLoad(context.Context, goservices.Configurable, error) {}
+
+func (r *startupReporter) SignalReceived(os.Signal) {}
+
+// finish finishes the startup with the given state, calling the handler with the report. Only the first call has
+// effect, and services started afterwards are not recorded.
+func (r *startupReporter) finish(state StartupState, err error) {
+	r.m.Lock()
+	if r.done {
+		r.m.Unlock()
+		return
+	}
+	r.done = true
+	report := StartupReport{
+		State:    state,
+		Err:      err,
+		Duration: time.Since(r.startedAt),
+		Services: append([]ServiceStartupReport{}, r.services...),
+	}
+	r.m.Unlock()
+
+	if r.handler != nil {
+		r.handler(report)
+	}
+}
@@ -0,0 +1,90 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	goservices "github.com/jamillosantos/go-services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplication_WithStartupReport(t *testing.T) {
+	newApp := func(ctx context.Context, reports *[]StartupReport) *Application {
+		return New().
+			WithContext(ctx).
+			WithSkipConfig(true).
+			WithDisableSystemServer(true).
+			WithStartupReport(func(report StartupReport) {
+				*reports = append(*reports, report)
+			})
+	}
+
+	t.Run("should report the started services when running", func(t *testing.T) {
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		defer cancelFunc()
+
+		var reports []StartupReport
+		app := newApp(ctx, &reports).WithStartupReport(func(report StartupReport) {
+			reports = append(reports, report)
+			cancelFunc()
+		})
+
+		err := app.RunE(func(ctx context.Context, app *Application) ([]goservices.Service, error) {
+			return []goservices.Service{&dummyResource{}}, nil
+		})
+		require.NoError(t, err)
+
+		require.Len(t, reports, 1)
+		assert.Equal(t, StartupRunning, reports[0].State)
+		assert.NoError(t, reports[0].Err)
+		require.Len(t, reports[0].Services, 1)
+		assert.Equal(t, "http", reports[0].Services[0].Name)
+		assert.True(t, reports[0].Services[0].Started)
+	})
+
+	t.Run("should report the failed service", func(t *testing.T) {
+		wantErr := errors.New("failed to start")
+
+		var reports []StartupReport
+		err := newApp(context.Background(), &reports).RunE(func(ctx context.Context, app *Application) ([]goservices.Service, error) {
+			return []goservices.Service{&dummyResource{}, &failingResource{err: wantErr}}, nil
+		})
+		require.ErrorIs(t, err, wantErr)
+
+		require.Len(t, reports, 1)
+		assert.Equal(t, StartupFailed, reports[0].State)
+		assert.ErrorIs(t, reports[0].Err, wantErr)
+		require.Len(t, reports[0].Services, 2)
+		assert.True(t, reports[0].Services[0].Started)
+		assert.Equal(t, "failing", reports[0].Services[1].Name)
+		assert.False(t, reports[0].Services[1].Started)
+		assert.ErrorIs(t, reports[0].Services[1].Err, wantErr)
+	})
+
+	t.Run("should report when the setup fails", func(t *testing.T) {
+		wantErr := errors.New("setup failed")
+
+		var reports []StartupReport
+		err := newApp(context.Background(), &reports).RunE(func(ctx context.Context, app *Application) ([]goservices.Service, error) {
+			return nil, wantErr
+		})
+		require.ErrorIs(t, err, wantErr)
+
+		require.Len(t, reports, 1)
+		assert.Equal(t, StartupFailed, reports[0].State)
+		assert.Empty(t, reports[0].Services)
+	})
+
+	t.Run("should report when there are no services", func(t *testing.T) {
+		var reports []StartupReport
+		err := newApp(context.Background(), &reports).RunE(func(ctx context.Context, app *Application) ([]goservices.Service, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+
+		require.Len(t, reports, 1)
+		assert.Equal(t, StartupNoServices, reports[0].State)
+	})
+}